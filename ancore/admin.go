@@ -0,0 +1,39 @@
+package ancore
+
+import (
+	"errors"
+	"net"
+	"net/http"
+)
+
+// serveAdmin listens on the admin address and serves, until the core
+// context is done:
+//
+//	/debug/anware/tap   live bus events as JSON lines (?source=&target=&type=)
+func (core *AnCore) serveAdmin() error {
+	ln, err := net.Listen("tcp", core.adminAddr)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/anware/tap", core.AnWare.TapHandler(nil))
+
+	srv := &http.Server{Handler: mux}
+	core.admin = srv
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			core.Logger.Error("[ANCORE] Admin endpoint stopped", "error", err)
+		}
+	}()
+
+	if core.ctx != nil {
+		go func() {
+			<-core.ctx.Done()
+			srv.Close()
+		}()
+	}
+
+	core.Logger.Info("[ANCORE] Admin endpoint listening", "addr", ln.Addr().String())
+	return nil
+}
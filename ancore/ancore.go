@@ -2,7 +2,11 @@ package ancore
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/Aninetix/core/aninterface"
@@ -20,12 +24,48 @@ type AnCore struct {
 	Logger aninterface.AnLogger
 	AnWare *anware.AnWare
 	Data   aninterface.StaticData
+
+	// Bridge links the AnWare to other processes; set by Run when
+	// --bridge_listen or --bridge_peers is given, closed by Shutdown.
+	Bridge *anware.Bridge
+
+	ctx          context.Context
+	recordPath   string
+	adminAddr    string
+	bridgeListen string
+	bridgePeers  []string
+	admin        *http.Server
 }
 
 type InitOptions struct {
 	LogPath    string
 	ConfigPath string
 	Debug      *bool
+
+	MissingConfig anware.MissingConfigPolicy
+	Enable        []string
+	Disable       []string
+
+	EnvPrefix  string
+	Args       []string
+	ArgsSet    bool
+	FlagOutput io.Writer
+
+	DefaultCommand string
+	Stdout         io.Writer
+
+	LogLevel    string
+	LogFormat   string
+	SlogHandler slog.Handler
+	LogOutput   []string
+	LogColor    bool
+	LogSinks    []aninterface.LogSink
+
+	RecordPath string
+	AdminAddr  string
+
+	BridgeListen string
+	BridgePeers  []string
 }
 
 type Option func(*InitOptions)
@@ -42,23 +82,187 @@ func WithDebug(b bool) Option {
 	return func(o *InitOptions) { o.Debug = &b }
 }
 
+// WithLogLevel sets the core logger level (trace ... fatal), overriding
+// --debug and the Config logging section.
+func WithLogLevel(level string) Option {
+	return func(o *InitOptions) { o.LogLevel = level }
+}
+
+// WithLogFormat selects the log line encoding: text (default), json or
+// logfmt. It overrides the Config logging section.
+func WithLogFormat(format string) Option {
+	return func(o *InitOptions) { o.LogFormat = format }
+}
+
+// WithSlogHandler sends every log entry to h instead of the log outputs. Levels
+// still apply; the format options are ignored.
+func WithSlogHandler(h slog.Handler) Option {
+	return func(o *InitOptions) { o.SlogHandler = h }
+}
+
+// WithLogOutput selects where logs go: file (default), stdout, stderr,
+// syslog, or several of them. It overrides the Config logging section.
+func WithLogOutput(outputs ...string) Option {
+	return func(o *InitOptions) { o.LogOutput = outputs }
+}
+
+// WithLogColor colours the level on stdout/stderr outputs (text format).
+func WithLogColor(on bool) Option {
+	return func(o *InitOptions) { o.LogColor = on }
+}
+
+// WithLogSink adds a custom sink next to the selected outputs. The caller
+// keeps ownership: the core flushes it but never closes it.
+func WithLogSink(sink aninterface.LogSink) Option {
+	return func(o *InitOptions) { o.LogSinks = append(o.LogSinks, sink) }
+}
+
+// WithRecordPath records the bus traffic to path (JSON lines) from Run until
+// Shutdown, for replay with anware.Replayer.
+func WithRecordPath(path string) Option {
+	return func(o *InitOptions) { o.RecordPath = path }
+}
+
+// WithAdminAddr serves the admin endpoints (e.g. /debug/anware/tap) on
+// addr from Run until the context is cancelled.
+func WithAdminAddr(addr string) Option {
+	return func(o *InitOptions) { o.AdminAddr = addr }
+}
+
+// WithBridgeListen accepts bridge peers on addr ("unix:/path" or
+// "tcp:host:port") from Run until the context is cancelled.
+func WithBridgeListen(addr string) Option {
+	return func(o *InitOptions) { o.BridgeListen = addr }
+}
+
+// WithBridgePeers links the AnWare to the bridges listening at addrs; the
+// links are re-established whenever they drop.
+func WithBridgePeers(addrs ...string) Option {
+	return func(o *InitOptions) { o.BridgePeers = append(o.BridgePeers, addrs...) }
+}
+
+// WithMissingConfigPolicy controls whether a registered module without a
+// section in the application Config fails the boot or is only warned about.
+func WithMissingConfigPolicy(p anware.MissingConfigPolicy) Option {
+	return func(o *InitOptions) { o.MissingConfig = p }
+}
+
+// WithEnabledModules forces modules on, even if their config is empty.
+func WithEnabledModules(names ...string) Option {
+	return func(o *InitOptions) { o.Enable = append(o.Enable, names...) }
+}
+
+// WithDisabledModules forces modules off, even if they are configured.
+func WithDisabledModules(names ...string) Option {
+	return func(o *InitOptions) { o.Disable = append(o.Disable, names...) }
+}
+
+// WithEnvPrefix lets every flag be set from PREFIX_<FLAG_NAME> when it is not
+// given on the command line (flags with an env tag keep their own variable).
+func WithEnvPrefix(prefix string) Option {
+	return func(o *InitOptions) { o.EnvPrefix = prefix }
+}
+
+// WithArgs parses args instead of os.Args[1:].
+func WithArgs(args []string) Option {
+	return func(o *InitOptions) {
+		o.Args = args
+		o.ArgsSet = true
+	}
+}
+
+// WithFlagOutput redirects flag usage and error messages (default os.Stderr).
+func WithFlagOutput(w io.Writer) Option {
+	return func(o *InitOptions) { o.FlagOutput = w }
+}
+
+// WithDefaultCommand sets the command Execute runs when none is given
+// (default "serve").
+func WithDefaultCommand(name string) Option {
+	return func(o *InitOptions) { o.DefaultCommand = name }
+}
+
+// WithStdout redirects the output of commands such as version.
+func WithStdout(w io.Writer) Option {
+	return func(o *InitOptions) { o.Stdout = w }
+}
+
+func (o InitOptions) flagOptions() []anflags.Option {
+	fo := []anflags.Option{anflags.WithEnvPrefix(o.EnvPrefix)}
+	if o.ArgsSet {
+		fo = append(fo, anflags.WithArgs(o.Args))
+	}
+	if o.FlagOutput != nil {
+		fo = append(fo, anflags.WithOutput(o.FlagOutput))
+	}
+	// flags contributed by modules: --<module>.<flag>
+	for _, desc := range anware.RegisteredModules() {
+		if desc.Flags != nil {
+			fo = append(fo, anflags.WithStruct(desc.Name, desc.Flags))
+		}
+	}
+	return fo
+}
+
 func ptrBool(b bool) *bool {
 	return &b
 }
 
+// InitCore parses flags, creates the logger and loads the config. It exits
+// the process on -h (0), on a command-line error (2) or on a config error
+// (1); use LoadCore to get the error instead.
 func InitCore[F any, C any](opts ...Option) (*F, *C, aninterface.AnLogger) {
+	flg, cfg, logger, err := LoadCore[F, C](opts...)
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			os.Exit(0)
+		case errors.Is(err, anflags.ErrDefinition):
+			panic(err)
+		case logger == nil:
+			// already reported with the usage by the FlagSet
+			os.Exit(2)
+		default:
+			os.Exit(1)
+		}
+	}
+
+	return flg, cfg, logger
+}
+
+// LoadCore is InitCore without exiting. Flags are parsed in a private
+// FlagSet, so it can be called several times (tests, embedding tools). The
+// logger is nil if flag parsing failed.
+func LoadCore[F any, C any](opts ...Option) (*F, *C, aninterface.AnLogger, error) {
 	// default options, e.g. from flags
 	var flg F
-	var cfg C
 
-	if err := anflags.ParseFlags(&flg); err != nil {
-		panic(err)
+	// options needed before parsing
+	var pre InitOptions
+	for _, opt := range opts {
+		opt(&pre)
 	}
 
+	if err := anflags.ParseFlags(&flg, pre.flagOptions()...); err != nil {
+		return nil, nil, nil, err
+	}
+
+	cfg, logger, err := loadRuntime[C](&flg, opts)
+	return &flg, cfg, logger, err
+}
+
+// loadRuntime creates the logger and loads the config once flags are parsed.
+func loadRuntime[C any](flg any, opts []Option) (*C, aninterface.AnLogger, error) {
+	var cfg C
+
 	o := InitOptions{
-		LogPath:    helpers.GetFieldString(&flg, "LogPath"),
-		ConfigPath: helpers.GetFieldString(&flg, "ConfigPath"),
-		Debug:      ptrBool(helpers.GetFieldBool(&flg, "Debug")),
+		LogPath:    helpers.GetFieldString(flg, "LogPath"),
+		ConfigPath: helpers.GetFieldString(flg, "ConfigPath"),
+		Debug:      ptrBool(helpers.GetFieldBool(flg, "Debug")),
+		LogLevel:   helpers.GetFieldString(flg, "LogLevel"),
+		LogFormat:  helpers.GetFieldString(flg, "LogFormat"),
+		LogOutput:  helpers.GetFieldList(flg, "LogOutput"),
+		LogColor:   helpers.GetFieldBool(flg, "LogColor"),
 	}
 
 	// override with provided optional params
@@ -67,32 +271,194 @@ func InitCore[F any, C any](opts ...Option) (*F, *C, aninterface.AnLogger) {
 	}
 
 	// --- LOGGER ---
-	logger := anlogger.NewLogger(o.LogPath, *o.Debug)
+	logger, err := o.newLogger(o.LogFormat, nil)
+	if err != nil {
+		logger.Error("Erreur format de log", "error", err)
+		return nil, logger, err
+	}
 
 	// --- CONFIG ---
 	if err := anconfig.LoadConfig(o.ConfigPath, &cfg); err != nil {
-		logger.Error(fmt.Sprintf("Erreur chargement config: %v", err))
-		os.Exit(1)
+		logger.Error("Erreur chargement config", "error", err)
+		return nil, logger, err
+	}
+
+	// --- LOG FORMAT & ROTATION --- flag/option > Config logging section
+	lc := findLogConfig(&cfg)
+	if lc != nil {
+		format := o.LogFormat
+		if format == "" {
+			format = lc.Format
+		}
+		// the boot logger has written nothing yet: replace it
+		if c, ok := logger.(io.Closer); ok {
+			c.Close()
+		}
+		if logger, err = o.newLogger(format, lc); err != nil {
+			logger.Error("Erreur configuration des logs", "error", err)
+			return nil, logger, err
+		}
+	}
+
+	// --- LOG LEVELS --- flag/option > Config logging section > --debug
+	level := o.LogLevel
+	if level == "" && lc != nil {
+		level = lc.Level
+	}
+	if level != "" {
+		lvl, err := aninterface.ParseLogLevel(level)
+		if err != nil {
+			logger.Error("Erreur niveau de log", "error", err)
+			return nil, logger, err
+		}
+		logger.SetLevel(lvl)
+	}
+	if _, err := lc.moduleLevels(); err != nil {
+		logger.Error("Erreur niveau de log", "error", err)
+		return nil, logger, err
 	}
 
-	return &flg, &cfg, logger
+	return &cfg, logger, nil
 }
 
-func BootCore(flg any, cfg any, logger aninterface.AnLogger, ctx context.Context, cancel context.CancelFunc) AnCore {
+// newLogger creates the core logger with the output settings of lc (may be
+// nil). On an invalid setting it still returns a usable text logger along
+// with the error.
+func (o InitOptions) newLogger(format string, lc *LogConfig) (aninterface.AnLogger, error) {
+	if o.SlogHandler != nil {
+		level := aninterface.LevelInfo
+		if *o.Debug {
+			level = aninterface.LevelDebug
+		}
+		return anlogger.NewSlogLogger(o.SlogHandler, level), nil
+	}
+	f, err := anlogger.ParseFormat(format)
+	if err != nil {
+		return anlogger.NewLogger(o.LogPath, *o.Debug), err
+	}
+	opts, err := lc.loggerOptions()
+	if err != nil {
+		return anlogger.NewLogger(o.LogPath, *o.Debug), err
+	}
+	opts = append(opts, anlogger.WithFormat(f))
+
+	sinks, err := o.logSinks(lc, opts)
+	if err != nil {
+		return anlogger.NewLogger(o.LogPath, *o.Debug), err
+	}
+	return anlogger.NewLogger(o.LogPath, *o.Debug, append(opts, anlogger.WithSinks(sinks...))...), nil
+}
+
+func BootCore(flg any, cfg any, logger aninterface.AnLogger, ctx context.Context, cancel context.CancelFunc, opts ...Option) AnCore {
+	// --enable / --disable / --record_path / --admin_addr / --bridge_listen /
+	// --bridge_peers, when the app Flags declare them
+	o := InitOptions{
+		Enable:       helpers.GetFieldList(flg, "Enable"),
+		Disable:      helpers.GetFieldList(flg, "Disable"),
+		RecordPath:   helpers.GetFieldString(flg, "RecordPath"),
+		AdminAddr:    helpers.GetFieldString(flg, "AdminAddr"),
+		BridgeListen: helpers.GetFieldString(flg, "BridgeListen"),
+		BridgePeers:  helpers.GetFieldList(flg, "BridgePeers"),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	lc := findLogConfig(cfg)
+	moduleLevels, err := lc.moduleLevels()
+	if err != nil {
+		logger.Error("[ANCORE] Invalid module log level", "error", err)
+	}
+
 	anStaticData := anlocal.LoadStaticData()
 
 	return AnCore{
 		Data:   anStaticData,
 		Logger: logger,
-		AnWare: anware.NewAnWare(ctx, cancel, logger),
+		AnWare: anware.NewAnWare(ctx, cancel, logger,
+			anware.WithMissingConfigPolicy(o.MissingConfig),
+			anware.WithModuleSwitches(o.Enable, o.Disable),
+			anware.WithModuleLogLevels(moduleLevels),
+			anware.WithModuleLogFiles(lc.moduleFiles()),
+		),
 		Flags:  flg,
 		Config: cfg,
+
+		ctx:          ctx,
+		recordPath:   o.RecordPath,
+		adminAddr:    o.AdminAddr,
+		bridgeListen: o.BridgeListen,
+		bridgePeers:  o.BridgePeers,
 	}
 }
 
-func (core *AnCore) Run() {
+// Run loads the registered modules and starts them. If any module config is
+// unusable, nothing is started and the aggregated anware.ConfigErrors is
+// returned.
+func (core *AnCore) Run() error {
 	core.Logger.Info("[ANCORE] Booting AnCore...")
-	core.AnWare.AutoLoadModules(core.Data, core.Config, core.Logger)
+	if err := core.AnWare.AutoLoadModules(core.Data, core.Config, core.Logger); err != nil {
+		core.Logger.Error("[ANCORE] Boot aborted", "error", err)
+		return err
+	}
+
+	// what was started so far, undone if a later step fails
+	var started []func()
+	abort := func(msg string, err error) error {
+		for i := len(started) - 1; i >= 0; i-- {
+			started[i]()
+		}
+		core.Logger.Error("[ANCORE] Boot aborted: "+msg, "error", err)
+		return err
+	}
+
+	if core.recordPath != "" {
+		rec, err := core.AnWare.RecordFile(core.recordPath, nil)
+		if err != nil {
+			return abort("cannot record the bus", err)
+		}
+		started = append(started, func() { rec.Stop() })
+		core.Logger.Info("[ANCORE] Recording bus traffic", "path", core.recordPath)
+	}
+	if core.adminAddr != "" {
+		if err := core.serveAdmin(); err != nil {
+			return abort("admin endpoint", err)
+		}
+		started = append(started, func() { core.admin.Close() })
+	}
+	if core.bridgeListen != "" || len(core.bridgePeers) > 0 {
+		b := anware.NewBridge(core.AnWare)
+		if core.bridgeListen != "" {
+			if err := b.Listen(core.bridgeListen); err != nil {
+				b.Close()
+				return abort("bridge", err)
+			}
+		}
+		for _, addr := range core.bridgePeers {
+			b.Connect(addr)
+		}
+		core.Bridge = b
+	}
 	core.AnWare.Run()
 	core.Logger.Info("[ANCORE] AnCore is running.")
+	return nil
+}
+
+// Shutdown closes the bridge and the admin endpoint started by Run, then
+// stops the AnWare (modules, recorders, logger). Like AnWare.Shutdown, it is
+// safe to call after an "exit" event.
+func (core *AnCore) Shutdown() {
+	if core.Bridge != nil {
+		core.Bridge.Close()
+	}
+	if core.admin != nil {
+		core.admin.Close()
+	}
+	core.AnWare.Shutdown()
+}
+
+// Check validates the configuration of every registered module without
+// building or starting any of them, and returns the load report.
+func (core *AnCore) Check() (anware.LoadReport, error) {
+	return core.AnWare.CheckModules(core.Config, core.Logger)
 }
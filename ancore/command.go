@@ -0,0 +1,333 @@
+package ancore
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Aninetix/core/aninterface"
+	"github.com/Aninetix/core/anware"
+	"github.com/Aninetix/core/internal/anconfig"
+	"github.com/Aninetix/core/internal/anflags"
+	"github.com/Aninetix/core/internal/anlocal"
+)
+
+// Command is one mode of a multi-mode binary: `app [flags] <command>
+// [command flags] [args]`.
+type Command struct {
+	Name  string
+	Usage string
+
+	// Flags optionally points to a struct parsed from the arguments that
+	// follow the command name.
+	Flags any
+
+	// NoConfig skips logger creation and config loading (e.g. version).
+	NoConfig bool
+
+	Run func(env *CommandEnv) error
+}
+
+// CommandEnv is what a Command receives once flags and config are loaded.
+type CommandEnv struct {
+	Ctx    context.Context
+	Cancel context.CancelFunc
+
+	Flags    any // application flags (*F)
+	Config   any // application config (*C), nil with NoConfig
+	Logger   aninterface.AnLogger
+	CmdFlags any      // Command.Flags, parsed
+	Args     []string // positional arguments after the command flags
+
+	Stdout  io.Writer
+	Options []Option
+
+	// LoadConfig decodes another config file into a fresh *C.
+	LoadConfig func(path string) (any, error)
+}
+
+// UsageError is a command-line error; the usage has already been printed.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+
+const DefaultCommand = "serve"
+
+var commandRegistry = map[string]Command{}
+
+func RegisterCommand(cmd Command) {
+	if _, ok := commandRegistry[cmd.Name]; ok {
+		panic("command already registered: " + cmd.Name)
+	}
+	commandRegistry[cmd.Name] = cmd
+}
+
+func init() {
+	RegisterCommand(Command{
+		Name:  DefaultCommand,
+		Usage: "boot and run all configured modules (default)",
+		Run:   serveCommand,
+	})
+	RegisterCommand(Command{
+		Name:  "check-config",
+		Usage: "validate module configs without starting them (extra args: more config files)",
+		Run:   checkConfigCommand,
+	})
+	RegisterCommand(Command{
+		Name:     "version",
+		Usage:    "print version information",
+		NoConfig: true,
+		Run:      versionCommand,
+	})
+	RegisterCommand(Command{
+		Name:     "help",
+		Usage:    "list commands, or show the flags of `help <command>`",
+		NoConfig: true,
+	})
+}
+
+// Execute parses the global flags F, picks the command named by the first
+// positional argument (DefaultCommand if none), parses its flags and runs
+// it. The config C is loaded unless the command sets NoConfig.
+func Execute[F any, C any](ctx context.Context, cancel context.CancelFunc, opts ...Option) error {
+	var flg F
+
+	var o InitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	out := o.flagOutput()
+	name := o.programName()
+
+	usage := func() { printUsage(out, name, &flg, o) }
+
+	rest, err := anflags.Parse(&flg, append(o.flagOptions(), anflags.WithUsage(usage))...)
+	if err != nil {
+		return usageError(err)
+	}
+
+	cmdName := o.DefaultCommand
+	if cmdName == "" {
+		cmdName = DefaultCommand
+	}
+	if len(rest) > 0 {
+		cmdName, rest = rest[0], rest[1:]
+	}
+
+	if cmdName == "help" {
+		if len(rest) > 0 {
+			cmd, ok := commandRegistry[rest[0]]
+			if !ok {
+				fmt.Fprintf(out, "unknown command %q\n\n", rest[0])
+				usage()
+				return &UsageError{Err: fmt.Errorf("unknown command %q", rest[0])}
+			}
+			printCommandUsage(out, name, cmd)
+			return nil
+		}
+		usage()
+		return nil
+	}
+
+	cmd, ok := commandRegistry[cmdName]
+	if !ok {
+		fmt.Fprintf(out, "unknown command %q\n\n", cmdName)
+		usage()
+		return &UsageError{Err: fmt.Errorf("unknown command %q", cmdName)}
+	}
+
+	cmdFlags := cmd.Flags
+	if cmdFlags == nil {
+		cmdFlags = &struct{}{}
+	}
+	args, err := anflags.Parse(cmdFlags,
+		anflags.WithName(name+" "+cmd.Name),
+		anflags.WithArgs(rest),
+		anflags.WithOutput(out),
+		anflags.WithEnvPrefix(o.EnvPrefix),
+		anflags.WithUsage(func() { printCommandUsage(out, name, cmd) }),
+	)
+	if err != nil {
+		return usageError(err)
+	}
+
+	env := &CommandEnv{
+		Ctx:      ctx,
+		Cancel:   cancel,
+		Flags:    &flg,
+		CmdFlags: cmd.Flags,
+		Args:     args,
+		Stdout:   o.stdout(),
+		Options:  opts,
+		LoadConfig: func(path string) (any, error) {
+			var cfg C
+			if err := anconfig.LoadConfig(path, &cfg); err != nil {
+				return nil, err
+			}
+			return &cfg, nil
+		},
+	}
+
+	if !cmd.NoConfig {
+		cfg, logger, err := loadRuntime[C](&flg, opts)
+		env.Logger = logger
+		// flush the buffered log files whatever the command does
+		if c, ok := logger.(io.Closer); ok {
+			defer c.Close()
+		}
+		if err != nil {
+			fmt.Fprintf(out, "%s: %v\n", cmd.Name, err)
+			return err
+		}
+		env.Config = cfg
+	}
+
+	if err := cmd.Run(env); err != nil {
+		fmt.Fprintf(out, "%s: %v\n", cmd.Name, err)
+		return err
+	}
+	return nil
+}
+
+// ExitCode maps an Execute error to a process exit status: 0 for nil or
+// -h, 2 for command-line errors, 1 otherwise.
+func ExitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, new(*UsageError)):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func usageError(err error) error {
+	if errors.Is(err, flag.ErrHelp) || errors.Is(err, anflags.ErrDefinition) {
+		return err
+	}
+	return &UsageError{Err: err}
+}
+
+func printUsage(w io.Writer, name string, flg any, o InitOptions) {
+	fmt.Fprintf(w, "Usage: %s [flags] <command> [command flags] [args]\n\nCommands:\n", name)
+
+	names := make([]string, 0, len(commandRegistry))
+	for n := range commandRegistry {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", n, commandRegistry[n].Usage)
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	if err := anflags.PrintDefaults(w, flg, o.flagOptions()...); err != nil {
+		fmt.Fprintln(w, err)
+	}
+}
+
+func printCommandUsage(w io.Writer, name string, cmd Command) {
+	fmt.Fprintf(w, "Usage: %s [flags] %s [command flags] [args]\n\n%s\n", name, cmd.Name, cmd.Usage)
+	if cmd.Flags == nil {
+		return
+	}
+	fmt.Fprintln(w, "\nCommand flags:")
+	if err := anflags.PrintDefaults(w, cmd.Flags); err != nil {
+		fmt.Fprintln(w, err)
+	}
+}
+
+func (o InitOptions) flagOutput() io.Writer {
+	if o.FlagOutput != nil {
+		return o.FlagOutput
+	}
+	return os.Stderr
+}
+
+func (o InitOptions) stdout() io.Writer {
+	if o.Stdout != nil {
+		return o.Stdout
+	}
+	return os.Stdout
+}
+
+func (o InitOptions) programName() string {
+	if len(os.Args) > 0 {
+		return filepath.Base(os.Args[0])
+	}
+	return "app"
+}
+
+// --- built-in commands ---
+
+func serveCommand(env *CommandEnv) error {
+	core := BootCore(env.Flags, env.Config, env.Logger, env.Ctx, env.Cancel, env.Options...)
+	if err := core.Run(); err != nil {
+		return err
+	}
+	<-env.Ctx.Done()
+	// stops the modules and flushes the logs; a no-op when an "exit" event
+	// already did it
+	core.Shutdown()
+	return nil
+}
+
+// checkConfigCommand is the CI dry run: it prints the auto-load report and
+// fails if any module config is unusable or rejected by Validate(). Extra
+// arguments are further config files to check the same way.
+func checkConfigCommand(env *CommandEnv) error {
+	failed := 0
+	if err := checkConfig(env, env.Config); err != nil {
+		fmt.Fprintf(env.Stdout, "FAIL: %v\n", err)
+		failed++
+	}
+
+	for _, path := range env.Args {
+		fmt.Fprintf(env.Stdout, "\n== %s\n", path)
+		cfg, err := env.LoadConfig(path)
+		if err == nil {
+			err = checkConfig(env, cfg)
+		}
+		if err != nil {
+			fmt.Fprintf(env.Stdout, "FAIL: %v\n", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d config(s) invalid", failed)
+	}
+	return nil
+}
+
+func checkConfig(env *CommandEnv, cfg any) error {
+	core := BootCore(env.Flags, cfg, env.Logger, env.Ctx, env.Cancel, env.Options...)
+
+	report, err := core.Check()
+	report.Print(env.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if n := report.Count(anware.ModuleRejected); n > 0 {
+		return fmt.Errorf("%d module(s) rejected", n)
+	}
+
+	fmt.Fprintf(env.Stdout, "config OK: %d module(s) would be loaded\n", report.Count(anware.ModuleLoaded))
+	return nil
+}
+
+func versionCommand(env *CommandEnv) error {
+	data := anlocal.LoadStaticData()
+	fmt.Fprintf(env.Stdout, "version %s (%s, %s/%s)\n", data.AnCoreVersion(), data.GoVersion(), data.OS(), data.Arch())
+	return nil
+}
@@ -0,0 +1,254 @@
+package ancore
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+	"github.com/Aninetix/core/internal/anlogger"
+)
+
+// LogConfig is the logging section of the application Config. Declare a
+// top-level field of this type in Config (e.g. Logging, tagged
+// json:"logging") and the core picks it up.
+type LogConfig struct {
+	// Level is the minimum level of the core logger (trace, debug, info,
+	// warn, error, fatal). Empty keeps the --debug behaviour.
+	Level string `json:"level"`
+
+	// Format is the line encoding: text (default), json or logfmt.
+	Format string `json:"format"`
+
+	// Modules holds per-module overrides, keyed by module name.
+	Modules map[string]ModuleLogConfig `json:"modules"`
+
+	// Outputs lists where logs go: file (default), stdout, stderr, syslog.
+	// Color colours the level on stdout/stderr.
+	Outputs []string      `json:"outputs"`
+	Color   bool          `json:"color"`
+	Syslog  *SyslogConfig `json:"syslog"`
+
+	// Sampling limits repeated messages on every output.
+	Sampling *LogSampling `json:"sampling"`
+
+	// Rotation applies to every log file; Files overrides it for the files
+	// of WithFile loggers, keyed by file name.
+	Rotation *LogRotation           `json:"rotation"`
+	Files    map[string]LogRotation `json:"files"`
+}
+
+// SyslogConfig selects the syslog server of the syslog output. Empty Network
+// and Address use the local syslog socket.
+type SyslogConfig struct {
+	Network string `json:"network"` // udp, tcp, unix...
+	Address string `json:"address"`
+	Tag     string `json:"tag"`
+}
+
+// LogSampling lets, per Interval and per level+message, the First entries
+// through, then one in Thereafter (0: none), and logs a "suppressed N
+// messages" summary at the end of each interval. Errors are never sampled;
+// First and Thereafter cannot both be 0.
+type LogSampling struct {
+	Interval   string `json:"interval"` // default "1s"
+	First      int    `json:"first"`
+	Thereafter int    `json:"thereafter"`
+}
+
+// LogRotation rotates a file when it grows past MaxSizeMB or is older than
+// Interval, then keeps at most MaxFiles rotated files younger than MaxAge.
+// Durations use time.ParseDuration syntax ("24h", "168h").
+type LogRotation struct {
+	MaxSizeMB int    `json:"max_size_mb"`
+	Interval  string `json:"interval"`
+	MaxAge    string `json:"max_age"`
+	MaxFiles  int    `json:"max_files"`
+	Compress  bool   `json:"compress"`
+}
+
+// ModuleLogConfig overrides the logging of one module: its minimum level
+// and, optionally, a file of its own in LogPath.
+type ModuleLogConfig struct {
+	Level string `json:"level"`
+	File  string `json:"file"`
+}
+
+// findLogConfig returns the first top-level LogConfig field of cfg.
+func findLogConfig(cfg any) *LogConfig {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Field(i)
+		if !rv.Type().Field(i).IsExported() {
+			continue
+		}
+		switch lc := f.Addr().Interface().(type) {
+		case *LogConfig:
+			return lc
+		case **LogConfig:
+			return *lc
+		}
+	}
+	return nil
+}
+
+// moduleFiles returns the module-specific log files of lc.
+func (lc *LogConfig) moduleFiles() map[string]string {
+	if lc == nil {
+		return nil
+	}
+	files := make(map[string]string, len(lc.Modules))
+	for name, mc := range lc.Modules {
+		if mc.File != "" {
+			files[name] = mc.File
+		}
+	}
+	return files
+}
+
+// moduleLevels parses the per-module levels of lc.
+func (lc *LogConfig) moduleLevels() (map[string]aninterface.LogLevel, error) {
+	if lc == nil {
+		return nil, nil
+	}
+	levels := make(map[string]aninterface.LogLevel, len(lc.Modules))
+	for name, mc := range lc.Modules {
+		if mc.Level == "" {
+			continue
+		}
+		lvl, err := aninterface.ParseLogLevel(mc.Level)
+		if err != nil {
+			return nil, fmt.Errorf("logging.modules.%s: %w", name, err)
+		}
+		levels[name] = lvl
+	}
+	return levels, nil
+}
+
+// loggerOptions converts the sampling and rotation settings of lc.
+func (lc *LogConfig) loggerOptions() ([]anlogger.Option, error) {
+	if lc == nil {
+		return nil, nil
+	}
+	var opts []anlogger.Option
+	if lc.Rotation != nil {
+		r, err := lc.Rotation.rotation()
+		if err != nil {
+			return nil, fmt.Errorf("logging.rotation: %w", err)
+		}
+		opts = append(opts, anlogger.WithRotation(r))
+	}
+	if lc.Sampling != nil {
+		smp := anlogger.Sampling{First: lc.Sampling.First, Thereafter: lc.Sampling.Thereafter}
+		if lc.Sampling.Interval != "" {
+			d, err := time.ParseDuration(lc.Sampling.Interval)
+			if err != nil {
+				return nil, fmt.Errorf("logging.sampling.interval: %w", err)
+			}
+			smp.Interval = d
+		}
+		if smp.Interval < 0 || smp.First < 0 || smp.Thereafter < 0 {
+			return nil, fmt.Errorf("logging.sampling: negative value")
+		}
+		if smp.First == 0 && smp.Thereafter == 0 {
+			return nil, fmt.Errorf("logging.sampling: first or thereafter must be set, or every message would be dropped")
+		}
+		opts = append(opts, anlogger.WithSampling(smp))
+	}
+	for name, lr := range lc.Files {
+		r, err := lr.rotation()
+		if err != nil {
+			return nil, fmt.Errorf("logging.files.%s: %w", name, err)
+		}
+		opts = append(opts, anlogger.WithFileRotation(name, r))
+	}
+	return opts, nil
+}
+
+func (lr LogRotation) rotation() (anlogger.Rotation, error) {
+	r := anlogger.Rotation{
+		MaxSize:  int64(lr.MaxSizeMB) << 20,
+		MaxFiles: lr.MaxFiles,
+		Compress: lr.Compress,
+	}
+	var err error
+	if lr.Interval != "" {
+		if r.Interval, err = time.ParseDuration(lr.Interval); err != nil {
+			return r, fmt.Errorf("interval: %w", err)
+		}
+	}
+	if lr.MaxAge != "" {
+		if r.MaxAge, err = time.ParseDuration(lr.MaxAge); err != nil {
+			return r, fmt.Errorf("max_age: %w", err)
+		}
+	}
+	if r.MaxSize < 0 || r.MaxFiles < 0 || r.Interval < 0 || r.MaxAge < 0 {
+		return r, fmt.Errorf("negative value")
+	}
+	return r, nil
+}
+
+// logSinks builds the outputs chosen by the options, else by lc, plus the
+// custom sinks. No sink at all means the default file output.
+func (o InitOptions) logSinks(lc *LogConfig, opts []anlogger.Option) ([]aninterface.LogSink, error) {
+	outputs := o.LogOutput
+	color := o.LogColor
+	var sl SyslogConfig
+	if lc != nil {
+		if len(outputs) == 0 {
+			outputs = lc.Outputs
+		}
+		color = color || lc.Color
+		if lc.Syslog != nil {
+			sl = *lc.Syslog
+		}
+	}
+	if len(outputs) == 0 && len(o.LogSinks) == 0 {
+		return nil, nil
+	}
+
+	var sinks []aninterface.LogSink
+	for _, out := range outputs {
+		switch out {
+		case "file":
+			sinks = append(sinks, anlogger.NewFileSink(o.LogPath, opts...))
+		case "stdout":
+			sinks = append(sinks, anlogger.NewConsoleSink(os.Stdout, append(opts, anlogger.WithColor(color))...))
+		case "stderr":
+			sinks = append(sinks, anlogger.NewConsoleSink(os.Stderr, append(opts, anlogger.WithColor(color))...))
+		case "syslog":
+			s, err := anlogger.NewSyslogSink(sl.Network, sl.Address, sl.Tag, opts...)
+			if err != nil {
+				closeSinks(sinks)
+				return nil, fmt.Errorf("log output syslog: %w", err)
+			}
+			sinks = append(sinks, s)
+		default:
+			closeSinks(sinks)
+			return nil, fmt.Errorf("unknown log output %q (file, stdout, stderr, syslog)", out)
+		}
+	}
+	for _, s := range o.LogSinks {
+		sinks = append(sinks, keepOpen{s})
+	}
+	return sinks, nil
+}
+
+func closeSinks(sinks []aninterface.LogSink) {
+	for _, s := range sinks {
+		s.Close()
+	}
+}
+
+// keepOpen protects a sink owned by the application from Close.
+type keepOpen struct {
+	aninterface.LogSink
+}
+
+func (k keepOpen) Close() error { return k.Flush() }
@@ -1,9 +1,111 @@
 package aninterface
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// AnLogger methods take a message and optional key/value fields, following
+// the log/slog convention: alternating keys and values, or slog.Attr:
+//
+//	logger.Info("module loaded", "module", name, "took", d)
+//
+// A plain logger.Info(msg) keeps working.
 type AnLogger interface {
-	Info(msg string)
-	Error(msg string)
-	Debug(msg string)
+	Trace(msg string, fields ...any)
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	Fatal(msg string, fields ...any) // logs then exits the process
+
+	// With returns a logger that adds fields to every entry. It shares the
+	// level of its parent.
+	With(fields ...any) AnLogger
+
+	// WithContext is With for the EventMeta carried by ctx, if any (see
+	// AnWareEvent.Context).
+	WithContext(ctx context.Context) AnLogger
+
+	// Level is the minimum level written; SetLevel changes it at runtime for
+	// this logger and every logger derived from it with WithFile.
+	Level() LogLevel
+	SetLevel(level LogLevel)
+
+	// WithLevel returns a logger writing to the same place with its own,
+	// independent level.
+	WithLevel(level LogLevel) AnLogger
 
 	WithFile(filename string) AnLogger
 }
+
+// LogEntry is one log call that passed the logger level.
+type LogEntry struct {
+	Time   time.Time
+	Level  LogLevel
+	Caller string // file.go:line
+	Msg    string
+	Fields []slog.Attr // With fields, then call fields
+	File   string      // name given to WithFile, "" otherwise
+}
+
+// LogSink is where a logger writes its entries: files, console, syslog, or
+// several at once. Implementations must be safe for concurrent use.
+type LogSink interface {
+	WriteEntry(e *LogEntry) error
+	Flush() error
+	Close() error
+}
+
+// LogLevel values follow log/slog (Debug -4, Info 0, Warn 4, Error 8).
+type LogLevel int32
+
+const (
+	LevelTrace LogLevel = -8
+	LevelDebug LogLevel = -4
+	LevelInfo  LogLevel = 0
+	LevelWarn  LogLevel = 4
+	LevelError LogLevel = 8
+	LevelFatal LogLevel = 12
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelTrace:
+		return "TRACE"
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "FATAL"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int32(l))
+}
+
+// ParseLogLevel accepts trace, debug, info, warn (warning), error and fatal,
+// in any case.
+func ParseLogLevel(s string) (LogLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", s)
+}
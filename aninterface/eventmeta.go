@@ -0,0 +1,44 @@
+package aninterface
+
+import "context"
+
+// EventMeta describes the bus event being handled. Carried by a context, it
+// lets AnLogger.WithContext tag log lines so that one request can be
+// followed across modules through its CorrelationID.
+type EventMeta struct {
+	Source        string
+	Target        string
+	Type          string
+	CorrelationID string
+}
+
+type eventMetaKey struct{}
+
+func ContextWithEventMeta(ctx context.Context, meta EventMeta) context.Context {
+	return context.WithValue(ctx, eventMetaKey{}, meta)
+}
+
+func EventMetaFromContext(ctx context.Context) (EventMeta, bool) {
+	if ctx == nil {
+		return EventMeta{}, false
+	}
+	meta, ok := ctx.Value(eventMetaKey{}).(EventMeta)
+	return meta, ok
+}
+
+// Fields returns the non-empty values as log fields: source, target, type,
+// correlation_id.
+func (m EventMeta) Fields() []any {
+	var fields []any
+	for _, kv := range [...][2]string{
+		{"source", m.Source},
+		{"target", m.Target},
+		{"type", m.Type},
+		{"correlation_id", m.CorrelationID},
+	} {
+		if kv[1] != "" {
+			fields = append(fields, kv[0], kv[1])
+		}
+	}
+	return fields
+}
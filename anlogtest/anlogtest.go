@@ -0,0 +1,229 @@
+// Package anlogtest provides an in-memory aninterface.AnLogger for module
+// unit tests: every entry is recorded with its level, message, fields and
+// caller, and can be inspected or waited for.
+//
+//	log := anlogtest.New()
+//	mod := newModule(cfg, log)
+//	mod.Start()
+//	log.AssertWaitFor(t, "connected", time.Second)
+//	log.AssertCount(t, aninterface.LevelError, 0)
+package anlogtest
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+	"github.com/Aninetix/core/internal/anlogger"
+)
+
+// Entry is one recorded log call. Fields are flattened: a group g holding
+// k is stored under "g.k".
+type Entry struct {
+	Time   time.Time
+	Level  aninterface.LogLevel
+	Msg    string
+	Caller string // file.go:line
+	File   string // name given to WithFile, "" otherwise
+	Fields map[string]any
+}
+
+// Logger records everything written through it and through the loggers
+// derived from it (With, WithFile, WithLevel, WithContext). Fatal is
+// recorded but does not exit.
+type Logger struct {
+	aninterface.AnLogger
+	rec *recorder
+}
+
+// New returns a capture logger at level trace.
+func New() *Logger {
+	rec := &recorder{notify: make(chan struct{})}
+	return &Logger{
+		AnLogger: anlogger.NewSinkLogger(rec, aninterface.LevelTrace, anlogger.WithExit(func(int) {})),
+		rec:      rec,
+	}
+}
+
+// Entries returns a copy of the recorded entries, oldest first.
+func (l *Logger) Entries() []Entry {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+	return append([]Entry(nil), l.rec.entries...)
+}
+
+// Reset forgets the recorded entries.
+func (l *Logger) Reset() {
+	l.rec.mu.Lock()
+	defer l.rec.mu.Unlock()
+	l.rec.entries = nil
+}
+
+// Filter returns the entries matching keep.
+func (l *Logger) Filter(keep func(Entry) bool) []Entry {
+	var out []Entry
+	for _, e := range l.Entries() {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Contains reports whether an entry message contains substr.
+func (l *Logger) Contains(substr string) bool {
+	_, ok := l.find(substr)
+	return ok
+}
+
+// Count returns the number of entries at level.
+func (l *Logger) Count(level aninterface.LogLevel) int {
+	return len(l.Filter(func(e Entry) bool { return e.Level == level }))
+}
+
+// WaitFor waits until an entry message contains substr, or timeout.
+func (l *Logger) WaitFor(substr string, timeout time.Duration) (Entry, bool) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		l.rec.mu.Lock()
+		notify := l.rec.notify
+		l.rec.mu.Unlock()
+
+		if e, ok := l.find(substr); ok {
+			return e, true
+		}
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return Entry{}, false
+		}
+	}
+}
+
+func (l *Logger) find(substr string) (Entry, bool) {
+	for _, e := range l.Entries() {
+		if strings.Contains(e.Msg, substr) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// ---- testing helpers ----
+
+// AssertContains fails tb if no entry message contains substr.
+func (l *Logger) AssertContains(tb testing.TB, substr string) Entry {
+	tb.Helper()
+	e, ok := l.find(substr)
+	if !ok {
+		tb.Errorf("no log entry containing %q\n%s", substr, l.dump())
+	}
+	return e
+}
+
+// AssertCount fails tb unless exactly n entries are at level.
+func (l *Logger) AssertCount(tb testing.TB, level aninterface.LogLevel, n int) {
+	tb.Helper()
+	if got := l.Count(level); got != n {
+		tb.Errorf("%d %s log entries, want %d\n%s", got, level, n, l.dump())
+	}
+}
+
+// AssertWaitFor stops the test (FailNow) if no entry message contains
+// substr within timeout.
+func (l *Logger) AssertWaitFor(tb testing.TB, substr string, timeout time.Duration) Entry {
+	tb.Helper()
+	e, ok := l.WaitFor(substr, timeout)
+	if !ok {
+		tb.Fatalf("no log entry containing %q after %s\n%s", substr, timeout, l.dump())
+	}
+	return e
+}
+
+// dump lists the recorded entries for failure messages.
+func (l *Logger) dump() string {
+	var b strings.Builder
+	b.WriteString("recorded entries:")
+	for _, e := range l.Entries() {
+		b.WriteString("\n  ")
+		b.WriteString(e.String())
+	}
+	return b.String()
+}
+
+// String formats e like the text log format, without the time.
+func (e Entry) String() string {
+	var b strings.Builder
+	b.WriteString("[" + e.Level.String() + "] [" + e.Caller + "] " + e.Msg)
+	for _, k := range sortedKeys(e.Fields) {
+		b.WriteString(" " + k + "=")
+		b.WriteString(slog.AnyValue(e.Fields[k]).String())
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ---- recorder: the LogSink behind Logger ----
+
+var _ aninterface.LogSink = (*recorder)(nil)
+
+type recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	notify  chan struct{} // closed and replaced on every entry
+}
+
+func (r *recorder) WriteEntry(le *aninterface.LogEntry) error {
+	e := Entry{
+		Time:   le.Time,
+		Level:  le.Level,
+		Msg:    le.Msg,
+		Caller: le.Caller,
+		File:   le.File,
+		Fields: map[string]any{},
+	}
+	for _, a := range le.Fields {
+		flatten(e.Fields, "", a)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	close(r.notify)
+	r.notify = make(chan struct{})
+	return nil
+}
+
+func (r *recorder) Flush() error { return nil }
+func (r *recorder) Close() error { return nil }
+
+func flatten(dst map[string]any, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			flatten(dst, prefix, ga)
+		}
+		return
+	}
+	if a.Key != "" {
+		dst[prefix+a.Key] = v.Any()
+	}
+}
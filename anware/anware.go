@@ -5,6 +5,7 @@ import (
 
 	"context"
 	"sync"
+	"sync/atomic"
 )
 
 type AnWareReply struct {
@@ -19,6 +20,17 @@ type AnWareEvent struct {
 	Data   any
 
 	ReplyTo chan AnWareReply
+
+	// CorrelationID follows a request across modules. Send fills it if
+	// empty; SendCtx copies it from the event being handled.
+	CorrelationID string
+
+	// fanout marks the copies made when routing a Target "*" event.
+	fanout bool
+
+	// set on events received through a Bridge
+	hops       int
+	fromBridge bool
 }
 
 type AnModule interface {
@@ -36,19 +48,71 @@ type AnWare struct {
 	bus    chan AnWareEvent
 	wg     sync.WaitGroup
 
+	// busMu guards busClosed: Send holds it for reading, shutdown for the close
+	busMu     sync.RWMutex
+	busClosed bool
+
 	context context.Context
 	cancel  context.CancelFunc
 
+	missingConfig MissingConfigPolicy
+	enable        map[string]bool
+	disable       map[string]bool
+	report        LoadReport
+
+	logMu     sync.Mutex
+	logLevels map[string]aninterface.LogLevel
+	logFiles  map[string]string
+	loggers   map[string]aninterface.AnLogger
+
+	intercept func(AnWareEvent)
+
+	obsMu     sync.RWMutex
+	observers map[int]func(AnWareEvent)
+	obsNext   int
+	recorders map[*Recorder]bool
+
+	bridge atomic.Pointer[Bridge]
+
+	stopOnce sync.Once
+
 	Logger aninterface.AnLogger
 }
 
-func NewAnWare(context context.Context, cancel context.CancelFunc, logger aninterface.AnLogger) *AnWare {
-	return &AnWare{
+type Option func(*AnWare)
+
+// WithMissingConfigPolicy sets how AutoLoadModules treats registered modules
+// that have no section in the application Config.
+func WithMissingConfigPolicy(p MissingConfigPolicy) Option {
+	return func(m *AnWare) { m.missingConfig = p }
+}
+
+// WithInterceptor hands every event given to Send, SendSync or Broadcast to
+// fn instead of the bus (broadcasts once, with Target "*"). It is meant for
+// test harnesses such as anwaretest; fn answers SendSync through ReplyTo.
+func WithInterceptor(fn func(AnWareEvent)) Option {
+	return func(m *AnWare) { m.intercept = fn }
+}
+
+func NewAnWare(context context.Context, cancel context.CancelFunc, logger aninterface.AnLogger, opts ...Option) *AnWare {
+	m := &AnWare{
 		routes:  make(map[string]chan AnWareEvent),
 		mods:    make(map[string]AnModule),
 		bus:     make(chan AnWareEvent, 256),
 		context: context,
 		cancel:  cancel,
 		Logger:  logger,
+
+		logLevels: make(map[string]aninterface.LogLevel),
+		logFiles:  make(map[string]string),
+		loggers:   make(map[string]aninterface.AnLogger),
+		observers: make(map[int]func(AnWareEvent)),
+		recorders: make(map[*Recorder]bool),
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
@@ -0,0 +1,686 @@
+package anware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bridge links this AnWare to AnWare instances in other processes, over
+// Unix sockets or TCP. Routing tables are exchanged at connect time and on
+// every change; an event whose target is not a local module is forwarded
+// to the peer that has it, SendSync replies come back the same way, and
+// broadcasts reach every linked process.
+//
+//	b := anware.NewBridge(mw)
+//	b.Listen("unix:/run/app/bridge.sock")
+//	b.Connect("tcp:10.0.0.2:7400") // reconnects until Close
+//
+// Payloads go through a Codec (DefaultCodecs by default): register the
+// payload types of bridged messages with RegisterPayload, and "<type>.reply"
+// for typed SendSync replies.
+type Bridge struct {
+	mw        *AnWare
+	node      string
+	codec     Codec
+	heartbeat time.Duration
+	maxHops   int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.Mutex
+	peers     map[*bridgePeer]bool
+	listeners []net.Listener
+	pending   map[uint64]pendingReply
+	nextID    uint64
+	seq       uint64
+	seen      map[string]bool // broadcast IDs already handled
+}
+
+type BridgeOption func(*Bridge)
+
+// WithBridgeNode names this process for the peers (default host:pid).
+func WithBridgeNode(name string) BridgeOption {
+	return func(b *Bridge) { b.node = name }
+}
+
+// WithBridgeCodec sets the payload codec (default DefaultCodecs). All the
+// linked processes must use compatible codecs.
+func WithBridgeCodec(c Codec) BridgeOption {
+	return func(b *Bridge) { b.codec = c }
+}
+
+// WithHeartbeat sets the ping interval (default 5s, also used for d <= 0);
+// a peer silent for three intervals is disconnected.
+func WithHeartbeat(d time.Duration) BridgeOption {
+	return func(b *Bridge) { b.heartbeat = d }
+}
+
+// WithMaxHops bounds how many bridges an event may cross (default 4, also
+// used for n <= 0).
+func WithMaxHops(n int) BridgeOption {
+	return func(b *Bridge) { b.maxHops = n }
+}
+
+const (
+	frameHello  = "hello"
+	frameRoutes = "routes"
+	frameEvent  = "event"
+	frameReply  = "reply"
+	framePing   = "ping"
+
+	bridgeReplyTimeout = 5 * time.Second
+	bridgeQueue        = 1024
+)
+
+// bridgeFrame is one JSON line on a bridge connection.
+type bridgeFrame struct {
+	Kind    string          `json:"kind"`
+	Node    string          `json:"node,omitempty"`
+	Routes  map[string]int  `json:"routes,omitempty"` // target -> distance in hops
+	Event   json.RawMessage `json:"event,omitempty"`  // MarshalEvent
+	Hops    int             `json:"hops,omitempty"`
+	ID      string          `json:"id,omitempty"` // broadcast ID
+	ReplyID uint64          `json:"reply_id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	RawData []byte          `json:"raw_data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type pendingReply struct {
+	ch      chan AnWareReply
+	peer    *bridgePeer
+	msgType string
+}
+
+// NewBridge attaches a bridge to m. It lives until Close or until the
+// AnWare context is done.
+func NewBridge(m *AnWare, opts ...BridgeOption) *Bridge {
+	host, _ := os.Hostname()
+	b := &Bridge{
+		mw:        m,
+		node:      fmt.Sprintf("%s:%d", host, os.Getpid()),
+		codec:     DefaultCodecs,
+		heartbeat: 5 * time.Second,
+		maxHops:   4,
+		peers:     map[*bridgePeer]bool{},
+		pending:   map[uint64]pendingReply{},
+		seen:      map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.heartbeat <= 0 {
+		b.heartbeat = 5 * time.Second
+	}
+	if b.maxHops <= 0 {
+		b.maxHops = 4
+	}
+	b.ctx, b.cancel = context.WithCancel(m.context)
+
+	m.bridge.Store(b)
+	go func() {
+		<-b.ctx.Done()
+		b.shutdown()
+	}()
+	return b
+}
+
+// ParseBridgeAddr splits "unix:/path" or "tcp:host:port" (the default
+// when there is no scheme) into a network and an address.
+func ParseBridgeAddr(s string) (network, addr string) {
+	for _, n := range []string{"unix", "tcp", "tcp4", "tcp6"} {
+		if rest, ok := strings.CutPrefix(s, n+":"); ok {
+			return n, rest
+		}
+	}
+	return "tcp", s
+}
+
+// Listen accepts peers on address ("unix:/path" or "tcp:host:port").
+func (b *Bridge) Listen(address string) error {
+	network, addr := ParseBridgeAddr(address)
+	if network == "unix" {
+		// stale socket of a previous run
+		if info, err := os.Stat(addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(addr)
+		}
+	}
+
+	ln, err := net.Listen(network, addr)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		ln.Close()
+		return errors.New("bridge closed")
+	}
+	b.listeners = append(b.listeners, ln)
+	b.mu.Unlock()
+
+	b.mw.Logger.Info("[ANWARE] Bridge listening", "node", b.node, "addr", address)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			b.wg.Add(1)
+			go func() {
+				defer b.wg.Done()
+				b.serve(conn)
+			}()
+		}
+	}()
+	return nil
+}
+
+// Connect links to the peer at address, in the background, and reconnects
+// with a growing delay (up to 30s) whenever the link drops.
+func (b *Bridge) Connect(address string) {
+	network, addr := ParseBridgeAddr(address)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		backoff := time.Second
+		for b.ctx.Err() == nil {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			conn, err := d.DialContext(b.ctx, network, addr)
+			if err == nil {
+				start := time.Now()
+				b.serve(conn)
+				if time.Since(start) > b.heartbeat {
+					backoff = time.Second
+				}
+			} else {
+				b.mw.Logger.Debug("[ANWARE] Bridge dial failed", "addr", address, "error", err)
+			}
+
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+		}
+	}()
+}
+
+// Close disconnects every peer and stops listening and reconnecting.
+func (b *Bridge) Close() error {
+	b.cancel()
+	b.shutdown()
+	b.wg.Wait()
+	b.mw.bridge.CompareAndSwap(b, nil)
+	return nil
+}
+
+func (b *Bridge) shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ln := range b.listeners {
+		ln.Close()
+	}
+	b.listeners = nil
+	for p := range b.peers {
+		p.conn.Close()
+	}
+}
+
+// Peers returns the names of the connected peers.
+func (b *Bridge) Peers() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var names []string
+	for p := range b.peers {
+		if p.node != "" {
+			names = append(names, p.node)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Routes returns, for each remote target, the peer events are sent to.
+func (b *Bridge) Routes() map[string]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	routes := map[string]string{}
+	for p := range b.peers {
+		for name := range p.routes {
+			if best := b.routeLocked(name); best != nil {
+				routes[name] = best.node
+			}
+		}
+	}
+	return routes
+}
+
+// ---- peers ----
+
+type bridgePeer struct {
+	b      *Bridge
+	conn   net.Conn
+	node   string         // known after hello
+	routes map[string]int // advertised by the peer, guarded by b.mu
+	out    chan bridgeFrame
+	done   chan struct{}
+}
+
+// serve runs one connection until it drops.
+func (b *Bridge) serve(conn net.Conn) {
+	p := &bridgePeer{
+		b:    b,
+		conn: conn,
+		out:  make(chan bridgeFrame, bridgeQueue),
+		done: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.ctx.Err() != nil {
+		b.mu.Unlock()
+		conn.Close()
+		return
+	}
+	b.peers[p] = true
+	hello := bridgeFrame{Kind: frameHello, Node: b.node, Routes: b.tableForLocked(p)}
+	b.mu.Unlock()
+
+	p.out <- hello
+	go p.writeLoop()
+
+	dec := json.NewDecoder(conn)
+	var err error
+	for {
+		conn.SetReadDeadline(time.Now().Add(3 * b.heartbeat))
+		var f bridgeFrame
+		if err = dec.Decode(&f); err != nil {
+			break
+		}
+		b.handle(p, f)
+	}
+
+	close(p.done)
+	conn.Close()
+	b.dropPeer(p)
+	if p.node != "" && b.ctx.Err() == nil {
+		b.mw.Logger.Warn("[ANWARE] Bridge peer disconnected", "peer", p.node, "error", err)
+	}
+}
+
+func (p *bridgePeer) writeLoop() {
+	enc := json.NewEncoder(p.conn)
+	ping := time.NewTicker(p.b.heartbeat)
+	defer ping.Stop()
+
+	write := func(f bridgeFrame) bool {
+		p.conn.SetWriteDeadline(time.Now().Add(3 * p.b.heartbeat))
+		if err := enc.Encode(f); err != nil {
+			p.conn.Close()
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case f := <-p.out:
+			if !write(f) {
+				return
+			}
+		case <-ping.C:
+			if !write(bridgeFrame{Kind: framePing}) {
+				return
+			}
+		}
+	}
+}
+
+// send queues f for p without blocking the caller.
+func (p *bridgePeer) send(f bridgeFrame) bool {
+	select {
+	case p.out <- f:
+		return true
+	case <-p.done:
+		return false
+	default:
+		p.b.mw.Logger.Warn("[ANWARE] Bridge queue full, frame dropped", "peer", p.name(), "kind", f.Kind)
+		return false
+	}
+}
+
+func (p *bridgePeer) name() string {
+	p.b.mu.Lock()
+	defer p.b.mu.Unlock()
+	return p.node
+}
+
+// dropPeer forgets p, fails the SendSync calls waiting on it and tells the
+// other peers about the lost routes.
+func (b *Bridge) dropPeer(p *bridgePeer) {
+	b.mu.Lock()
+	delete(b.peers, p)
+	var failed []pendingReply
+	for id, pr := range b.pending {
+		if pr.peer == p {
+			failed = append(failed, pr)
+			delete(b.pending, id)
+		}
+	}
+	hadRoutes := len(p.routes) > 0
+	b.mu.Unlock()
+
+	for _, pr := range failed {
+		pr.ch <- AnWareReply{Err: fmt.Errorf("bridge peer %s disconnected", p.node)}
+	}
+	if hadRoutes {
+		b.advertise()
+	}
+}
+
+// ---- routing tables ----
+
+// routeLocked returns the peer closest to target, nil if none.
+func (b *Bridge) routeLocked(target string) *bridgePeer {
+	var best *bridgePeer
+	bestHops := b.maxHops
+	for p := range b.peers {
+		if h, ok := p.routes[target]; ok && h+1 <= bestHops {
+			best, bestHops = p, h+1
+		}
+	}
+	return best
+}
+
+// tableForLocked is what we advertise to p: local modules at distance 0,
+// and the targets reached through the other peers (split horizon).
+func (b *Bridge) tableForLocked(to *bridgePeer) map[string]int {
+	table := map[string]int{}
+	for name := range b.mw.mods {
+		table[name] = 0
+	}
+	for p := range b.peers {
+		if p == to {
+			continue
+		}
+		for name, h := range p.routes {
+			if _, local := b.mw.mods[name]; local || h+1 >= b.maxHops {
+				continue
+			}
+			if cur, ok := table[name]; !ok || h+1 < cur {
+				table[name] = h + 1
+			}
+		}
+	}
+	return table
+}
+
+// advertise sends the current routing table to every peer.
+func (b *Bridge) advertise() {
+	b.mu.Lock()
+	type update struct {
+		p *bridgePeer
+		f bridgeFrame
+	}
+	var updates []update
+	for p := range b.peers {
+		if p.node == "" {
+			continue
+		}
+		updates = append(updates, update{p, bridgeFrame{Kind: frameRoutes, Routes: b.tableForLocked(p)}})
+	}
+	b.mu.Unlock()
+
+	for _, u := range updates {
+		u.p.send(u.f)
+	}
+}
+
+// ---- incoming frames ----
+
+func (b *Bridge) handle(p *bridgePeer, f bridgeFrame) {
+	switch f.Kind {
+	case frameHello, frameRoutes:
+		b.mu.Lock()
+		if f.Kind == frameHello {
+			p.node = f.Node
+		}
+		changed := !maps.Equal(p.routes, f.Routes)
+		p.routes = f.Routes
+		b.mu.Unlock()
+
+		if f.Kind == frameHello {
+			b.mw.Logger.Info("[ANWARE] Bridge peer connected", "peer", p.node, "routes", len(f.Routes))
+		}
+		if changed {
+			b.advertise()
+		}
+
+	case frameEvent:
+		b.handleEvent(p, f)
+
+	case frameReply:
+		b.mu.Lock()
+		pr, ok := b.pending[f.ReplyID]
+		delete(b.pending, f.ReplyID)
+		b.mu.Unlock()
+		if !ok {
+			return
+		}
+
+		var reply AnWareReply
+		if raw := payloadOf(f.Data, f.RawData); raw != nil {
+			data, err := b.codec.Decode(pr.msgType+".reply", raw)
+			if err != nil {
+				reply.Err = fmt.Errorf("bridge reply %q: %w", pr.msgType, err)
+			}
+			reply.Data = data
+		}
+		if f.Error != "" {
+			reply.Err = errors.New(f.Error)
+		}
+		pr.ch <- reply
+
+	case framePing:
+		// the read deadline is already pushed back
+	}
+}
+
+func (b *Bridge) handleEvent(p *bridgePeer, f bridgeFrame) {
+	ev, err := UnmarshalEvent(f.Event, b.codec)
+	if err != nil {
+		b.mw.Logger.Warn("[ANWARE] Bridge event dropped", "peer", p.node, "error", err)
+		if f.ReplyID != 0 {
+			p.send(bridgeFrame{Kind: frameReply, ReplyID: f.ReplyID, Error: err.Error()})
+		}
+		return
+	}
+	ev.hops = f.Hops
+	ev.fromBridge = true
+
+	if b.ctx.Err() != nil {
+		return
+	}
+
+	if ev.Target == "*" {
+		b.mu.Lock()
+		if b.seen[f.ID] {
+			b.mu.Unlock()
+			return
+		}
+		if len(b.seen) > 4096 {
+			b.seen = map[string]bool{}
+		}
+		b.seen[f.ID] = true
+		b.mu.Unlock()
+
+		b.mw.Send(ev)
+		if f.Hops < b.maxHops {
+			f.Hops++
+			b.sendAll(f, p)
+		}
+		return
+	}
+
+	if f.ReplyID != 0 {
+		ch := make(chan AnWareReply, 1)
+		ev.ReplyTo = ch
+		go b.awaitReply(p, f.ReplyID, ev.Type, ch)
+	}
+	b.mw.Send(ev)
+}
+
+// awaitReply sends back to p the answer to a bridged SendSync.
+func (b *Bridge) awaitReply(p *bridgePeer, id uint64, msgType string, ch chan AnWareReply) {
+	f := bridgeFrame{Kind: frameReply, ReplyID: id}
+
+	select {
+	case reply := <-ch:
+		if reply.Err != nil {
+			f.Error = reply.Err.Error()
+		}
+		if reply.Data != nil {
+			raw, err := b.codec.Encode(msgType+".reply", reply.Data)
+			switch {
+			case err != nil:
+				f.Error = fmt.Sprintf("bridge reply %q: %v", msgType, err)
+			case json.Valid(raw):
+				f.Data = raw
+			default:
+				f.RawData = raw
+			}
+		}
+	case <-time.After(bridgeReplyTimeout):
+		f.Error = fmt.Sprintf("timeout waiting reply from %s on %s", msgType, b.node)
+	case <-b.ctx.Done():
+		return
+	}
+	p.send(f)
+}
+
+func payloadOf(data json.RawMessage, raw []byte) []byte {
+	if len(data) > 0 {
+		return data
+	}
+	return raw
+}
+
+// ---- outgoing events ----
+
+// forward sends msg to the peer that has its target. It returns false when
+// no peer has it.
+func (b *Bridge) forward(msg AnWareEvent) bool {
+	b.mu.Lock()
+	p := b.routeLocked(msg.Target)
+	b.mu.Unlock()
+	if p == nil {
+		return false
+	}
+
+	fail := func(err error) bool {
+		b.mw.Logger.Warn("[ANWARE] Bridge event dropped", append(msg.logFields(), "error", err)...)
+		if msg.ReplyTo != nil {
+			msg.ReplyTo <- AnWareReply{Err: err}
+		}
+		return true
+	}
+
+	if msg.hops >= b.maxHops {
+		return fail(fmt.Errorf("too many bridge hops to %s", msg.Target))
+	}
+	raw, err := MarshalEvent(msg, b.codec)
+	if err != nil {
+		return fail(err)
+	}
+
+	f := bridgeFrame{Kind: frameEvent, Event: raw, Hops: msg.hops + 1}
+	if msg.ReplyTo != nil {
+		f.ReplyID = b.addPending(msg.ReplyTo, p, msg.Type)
+	}
+	if !p.send(f) {
+		if f.ReplyID != 0 {
+			b.mu.Lock()
+			delete(b.pending, f.ReplyID)
+			b.mu.Unlock()
+		}
+		return fail(fmt.Errorf("bridge peer %s unavailable", p.name()))
+	}
+	return true
+}
+
+func (b *Bridge) addPending(ch chan AnWareReply, p *bridgePeer, msgType string) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := b.nextID
+	b.pending[id] = pendingReply{ch: ch, peer: p, msgType: msgType}
+
+	// SendSync has given up by then
+	time.AfterFunc(2*bridgeReplyTimeout, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.pending, id)
+	})
+	return id
+}
+
+// broadcastOut sends a local broadcast to every peer.
+func (b *Bridge) broadcastOut(msg AnWareEvent) {
+	if msg.hops >= b.maxHops {
+		return
+	}
+	raw, err := MarshalEvent(AnWareEvent{
+		Source:        msg.Source,
+		Target:        "*",
+		Type:          msg.Type,
+		Data:          msg.Data,
+		CorrelationID: msg.CorrelationID,
+	}, b.codec)
+	if err != nil {
+		b.mw.Logger.Warn("[ANWARE] Bridge broadcast dropped", append(msg.logFields(), "error", err)...)
+		return
+	}
+
+	b.mu.Lock()
+	b.seq++
+	id := fmt.Sprintf("%s#%d", b.node, b.seq)
+	b.seen[id] = true
+	b.mu.Unlock()
+
+	b.sendAll(bridgeFrame{Kind: frameEvent, Event: raw, Hops: msg.hops + 1, ID: id}, nil)
+}
+
+// sendAll queues f for every connected peer but except.
+func (b *Bridge) sendAll(f bridgeFrame, except *bridgePeer) {
+	b.mu.Lock()
+	var peers []*bridgePeer
+	for p := range b.peers {
+		if p != except && p.node != "" {
+			peers = append(peers, p)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, p := range peers {
+		p.send(f)
+	}
+}
@@ -0,0 +1,199 @@
+package anware
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aninetix/core/anlogtest"
+)
+
+// echoModule answers SendSync with "<name>:<data>" and records what it got.
+type echoModule struct {
+	name string
+	ctx  context.Context
+	in   <-chan AnWareEvent
+
+	mu  sync.Mutex
+	got []AnWareEvent
+}
+
+func (e *echoModule) Name() string { return e.name }
+
+func (e *echoModule) Param(ctx context.Context, in <-chan AnWareEvent, mw *AnWare) {
+	e.ctx, e.in = ctx, in
+}
+
+func (e *echoModule) Start() {
+	for {
+		select {
+		case <-e.ctx.Done():
+			return
+		case ev := <-e.in:
+			e.mu.Lock()
+			e.got = append(e.got, ev)
+			e.mu.Unlock()
+			if ev.ReplyTo != nil {
+				ev.ReplyTo <- AnWareReply{Data: e.name + ":" + ev.Data.(string)}
+			}
+		}
+	}
+}
+
+func (e *echoModule) Stop() error { return nil }
+
+func (e *echoModule) received(msgType string) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	n := 0
+	for _, ev := range e.got {
+		if ev.Type == msgType {
+			n++
+		}
+	}
+	return n
+}
+
+func newBridgeNode(t *testing.T, mods ...*echoModule) *AnWare {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewAnWare(ctx, cancel, anlogtest.New())
+	for _, mod := range mods {
+		m.mods[mod.name] = mod
+		m.routes[mod.name] = make(chan AnWareEvent, 128)
+	}
+	m.Run()
+	t.Cleanup(m.Shutdown)
+	return m
+}
+
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBridgeTwoNodes(t *testing.T) {
+	alpha, beta := &echoModule{name: "alpha"}, &echoModule{name: "beta"}
+	a := newBridgeNode(t, alpha)
+	b := newBridgeNode(t, beta)
+
+	addr := "unix:" + filepath.Join(t.TempDir(), "bridge.sock")
+	ba := NewBridge(a, WithBridgeNode("A"), WithHeartbeat(50*time.Millisecond))
+	if err := ba.Listen(addr); err != nil {
+		t.Fatal(err)
+	}
+	bb := NewBridge(b, WithBridgeNode("B"), WithHeartbeat(50*time.Millisecond))
+	bb.Connect(addr)
+	defer bb.Close()
+
+	waitUntil(t, "routes", func() bool {
+		return ba.Routes()["beta"] == "B" && bb.Routes()["alpha"] == "A"
+	})
+
+	// Send
+	a.Send(AnWareEvent{Source: "alpha", Target: "beta", Type: "note", Data: "hi"})
+	waitUntil(t, "forwarded Send", func() bool { return beta.received("note") == 1 })
+
+	// SendSync reply
+	res, err := a.SendSync("alpha", "beta", "echo", "ping")
+	if err != nil {
+		t.Fatalf("SendSync: %v", err)
+	}
+	if res != "beta:ping" {
+		t.Fatalf("SendSync reply = %v, want beta:ping", res)
+	}
+
+	// unknown targets still fail
+	if _, err := a.SendSync("alpha", "gamma", "echo", "ping"); err == nil {
+		t.Fatal("SendSync to an unknown target succeeded")
+	}
+
+	// broadcast, both ways, delivered once
+	a.Broadcast(AnWareEvent{Source: "alpha", Type: "hello", Data: "a"})
+	b.Send(AnWareEvent{Source: "beta", Target: "*", Type: "hello", Data: "b"})
+	waitUntil(t, "broadcasts", func() bool {
+		return alpha.received("hello") == 1 && beta.received("hello") == 1
+	})
+
+	// heartbeats keep an idle link up
+	time.Sleep(300 * time.Millisecond)
+	if got := bb.Peers(); len(got) != 1 || got[0] != "A" {
+		t.Fatalf("peers after idle = %v", got)
+	}
+
+	// peer drop: routes disappear
+	ba.Close()
+	waitUntil(t, "route removal", func() bool { return len(bb.Routes()) == 0 })
+	if _, err := b.SendSync("beta", "alpha", "echo", "ping"); err == nil {
+		t.Fatal("SendSync across a closed bridge succeeded")
+	}
+
+	// reconnect once a listener is back
+	ba = NewBridge(a, WithBridgeNode("A2"), WithHeartbeat(50*time.Millisecond))
+	if err := ba.Listen(addr); err != nil {
+		t.Fatal(err)
+	}
+	defer ba.Close()
+	waitUntil(t, "reconnection", func() bool { return bb.Routes()["alpha"] == "A2" })
+
+	res, err = b.SendSync("beta", "alpha", "echo", "back")
+	if err != nil || res != "alpha:back" {
+		t.Fatalf("SendSync after reconnect = %v, %v", res, err)
+	}
+}
+
+func TestBridgeDefaults(t *testing.T) {
+	m := newBridgeNode(t)
+	b := NewBridge(m, WithHeartbeat(0), WithMaxHops(-1))
+	defer b.Close()
+	if b.heartbeat != 5*time.Second || b.maxHops != 4 {
+		t.Fatalf("heartbeat = %v, maxHops = %d", b.heartbeat, b.maxHops)
+	}
+}
+
+func TestBridgeShutdownWhileReceiving(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		sink := &echoModule{name: "sink"}
+		a := newBridgeNode(t, sink)
+		b := newBridgeNode(t)
+
+		addr := "unix:" + filepath.Join(t.TempDir(), "bridge.sock")
+		ba := NewBridge(a, WithBridgeNode("A"))
+		if err := ba.Listen(addr); err != nil {
+			t.Fatal(err)
+		}
+		bb := NewBridge(b, WithBridgeNode("B"))
+		bb.Connect(addr)
+		waitUntil(t, "routes", func() bool { return bb.Routes()["sink"] == "A" })
+
+		// B floods A, which shuts down in the middle of it
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					b.Send(AnWareEvent{Source: "flood", Target: "sink", Type: "flood", Data: "x"})
+					b.Broadcast(AnWareEvent{Source: "flood", Type: "flood", Data: "x"})
+				}
+			}
+		}()
+		waitUntil(t, "traffic", func() bool { return sink.received("flood") > 0 })
+		a.Shutdown()
+
+		close(stop)
+		<-done
+		bb.Close()
+	}
+}
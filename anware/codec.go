@@ -0,0 +1,188 @@
+package anware
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Codec serialises event payloads, e.g. for recordings. msgType is the
+// event Type, so that a codec can pick the Go type to decode into.
+type Codec interface {
+	Encode(msgType string, data any) ([]byte, error)
+	Decode(msgType string, raw []byte) (any, error)
+}
+
+// JSONCodec encodes payloads as JSON. Without type information it decodes
+// into generic values (map[string]any, []any, float64, string, bool).
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(_ string, data any) ([]byte, error) {
+	return json.Marshal(data)
+}
+
+func (JSONCodec) Decode(_ string, raw []byte) (any, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Encoding turns a payload into bytes and back. JSONEncoding and
+// GobEncoding are provided; others (protobuf, msgpack...) only need these
+// three methods.
+type Encoding interface {
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(raw []byte, ptr any) error
+}
+
+type JSONEncoding struct{}
+
+func (JSONEncoding) Name() string                        { return "json" }
+func (JSONEncoding) Marshal(v any) ([]byte, error)       { return json.Marshal(v) }
+func (JSONEncoding) Unmarshal(raw []byte, ptr any) error { return json.Unmarshal(raw, ptr) }
+
+type GobEncoding struct{}
+
+func (GobEncoding) Name() string { return "gob" }
+
+func (GobEncoding) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobEncoding) Unmarshal(raw []byte, ptr any) error {
+	return gob.NewDecoder(bytes.NewReader(raw)).Decode(ptr)
+}
+
+// CodecRegistry is a Codec that knows, for each registered message type,
+// the Go type of its payload and its Encoding, so that payloads decode back
+// to the same type. Unregistered types go to Fallback (JSONCodec: generic
+// values), or fail if Fallback is nil.
+type CodecRegistry struct {
+	Fallback Codec
+
+	mu    sync.RWMutex
+	types map[string]payloadType
+}
+
+type payloadType struct {
+	typ reflect.Type
+	enc Encoding
+}
+
+var _ Codec = (*CodecRegistry)(nil)
+
+// DefaultCodecs is the registry used by recordings, taps and bridges when
+// no codec is given. RegisterPayload adds to it.
+var DefaultCodecs = NewCodecRegistry()
+
+func NewCodecRegistry() *CodecRegistry {
+	return &CodecRegistry{Fallback: JSONCodec{}, types: map[string]payloadType{}}
+}
+
+// RegisterPayload registers in DefaultCodecs the payload of msgType with
+// the type of prototype (a value or a pointer, e.g. GetUser{} or
+// &GetUser{}) and enc (JSONEncoding if nil). It panics on a duplicate, like
+// RegisterModule.
+func RegisterPayload(msgType string, prototype any, enc Encoding) {
+	if err := DefaultCodecs.Register(msgType, prototype, enc); err != nil {
+		panic(err)
+	}
+}
+
+// Register is RegisterPayload on r, returning the error.
+func (r *CodecRegistry) Register(msgType string, prototype any, enc Encoding) error {
+	if prototype == nil {
+		return fmt.Errorf("codec: %s: nil prototype", msgType)
+	}
+	if enc == nil {
+		enc = JSONEncoding{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.types[msgType]; ok {
+		return fmt.Errorf("codec: payload already registered for %s", msgType)
+	}
+	r.types[msgType] = payloadType{typ: reflect.TypeOf(prototype), enc: enc}
+	return nil
+}
+
+// Lookup returns the payload type and encoding registered for msgType.
+func (r *CodecRegistry) Lookup(msgType string) (reflect.Type, Encoding, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	pt, ok := r.types[msgType]
+	return pt.typ, pt.enc, ok
+}
+
+func (r *CodecRegistry) Encode(msgType string, data any) ([]byte, error) {
+	typ, enc, ok := r.Lookup(msgType)
+	if !ok {
+		if r.Fallback == nil {
+			return nil, fmt.Errorf("codec: no payload registered for %s", msgType)
+		}
+		return r.Fallback.Encode(msgType, data)
+	}
+	if got := reflect.TypeOf(data); got != typ {
+		return nil, fmt.Errorf("codec: %s payload is %v, registered %v", msgType, got, typ)
+	}
+	return enc.Marshal(data)
+}
+
+func (r *CodecRegistry) Decode(msgType string, raw []byte) (any, error) {
+	typ, enc, ok := r.Lookup(msgType)
+	if !ok {
+		if r.Fallback == nil {
+			return nil, fmt.Errorf("codec: no payload registered for %s", msgType)
+		}
+		return r.Fallback.Decode(msgType, raw)
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		ptr := reflect.New(typ.Elem())
+		if err := enc.Unmarshal(raw, ptr.Interface()); err != nil {
+			return nil, fmt.Errorf("codec: %s: %w", msgType, err)
+		}
+		return ptr.Interface(), nil
+	}
+	ptr := reflect.New(typ)
+	if err := enc.Unmarshal(raw, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("codec: %s: %w", msgType, err)
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// MarshalEvent serialises ev (without ReplyTo) with codec, DefaultCodecs
+// if nil. The result is a RecordedEvent in JSON.
+func MarshalEvent(ev AnWareEvent, codec Codec) ([]byte, error) {
+	if codec == nil {
+		codec = DefaultCodecs
+	}
+	rec := NewRecordedEvent(ev, codec)
+	if rec.EncodeError != "" {
+		return nil, fmt.Errorf("marshal %s->%s %q: %s", ev.Source, ev.Target, ev.Type, rec.EncodeError)
+	}
+	return json.Marshal(rec)
+}
+
+// UnmarshalEvent is the reverse of MarshalEvent. ReplyTo is nil.
+func UnmarshalEvent(raw []byte, codec Codec) (AnWareEvent, error) {
+	if codec == nil {
+		codec = DefaultCodecs
+	}
+	var rec RecordedEvent
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return AnWareEvent{}, err
+	}
+	return rec.Event(codec)
+}
@@ -0,0 +1,108 @@
+package anware
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Aninetix/core/internal/helpers"
+)
+
+// DefaultValue records a module config field set from its `default` tag.
+type DefaultValue struct {
+	Field string
+	Value string
+}
+
+// applyDefaults sets every zero field of a module section that carries a
+// `default:"..."` tag, using the same syntax as flags (durations like "5s",
+// comma-separated slices). Nested structs and non-nil struct pointers are
+// processed recursively, as are the elements of struct slices; nil struct
+// pointers stay nil (absent).
+//
+// Bool and numeric fields must be pointers (*bool, *int, *time.Duration...):
+// since defaults apply after the JSON is decoded, "retries": 0 would
+// otherwise be replaced by the default. A nil pointer gets the default.
+func applyDefaults(cfg any) ([]DefaultValue, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	if v.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var out []DefaultValue
+	err := defaultStruct(v, "", &out)
+	return out, err
+}
+
+func defaultStruct(v reflect.Value, prefix string, out *[]DefaultValue) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+		if !sf.IsExported() || !field.CanSet() {
+			continue
+		}
+		fieldPath := prefix + sf.Name
+
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			if isScalarZeroable(field.Type()) {
+				return fmt.Errorf(
+					"%w: %s default %q: an explicit zero could not be told from an unset value, use *%s",
+					ErrConfigDefault, fieldPath, def, field.Type(),
+				)
+			}
+			if field.IsZero() {
+				if err := setDefault(field, def); err != nil {
+					return fmt.Errorf("%w: %s default %q: %v", ErrConfigDefault, fieldPath, def, err)
+				}
+				*out = append(*out, DefaultValue{Field: fieldPath, Value: def})
+				continue
+			}
+		}
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			if err := defaultStruct(field, fieldPath+".", out); err != nil {
+				return err
+			}
+		case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+			if err := defaultStruct(field.Elem(), fieldPath+".", out); err != nil {
+				return err
+			}
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < field.Len(); j++ {
+				if err := defaultStruct(field.Index(j), fmt.Sprintf("%s[%d].", fieldPath, j), out); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// setDefault parses def into field, allocating nil scalar pointers.
+func setDefault(field reflect.Value, def string) error {
+	if field.Kind() != reflect.Ptr || field.Type().Elem().Kind() == reflect.Struct {
+		return helpers.SetFromString(field, def)
+	}
+	p := reflect.New(field.Type().Elem())
+	if err := helpers.SetFromString(p.Elem(), def); err != nil {
+		return err
+	}
+	field.Set(p)
+	return nil
+}
+
+// isScalarZeroable reports whether t is a bool or number type, whose zero
+// value is a legitimate config value.
+func isScalarZeroable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package anware
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+	"github.com/Aninetix/core/anlogtest"
+)
+
+func TestDefaults(t *testing.T) {
+	type retry struct {
+		Max *int `default:"3"`
+	}
+	type config struct {
+		Host    string         `default:"localhost"`
+		Peers   []string       `default:"a,b"`
+		Timeout *time.Duration `default:"5s"`
+		Enabled *bool          `default:"true"`
+		Retry   retry
+	}
+
+	cfg := &config{Host: "db", Enabled: ptr(false), Retry: retry{Max: ptr(0)}}
+	got, err := applyDefaults(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Host != "db" || len(cfg.Peers) != 2 || *cfg.Timeout != 5*time.Second {
+		t.Errorf("cfg = %+v", cfg)
+	}
+	// explicit zero values are kept
+	if *cfg.Enabled || *cfg.Retry.Max != 0 {
+		t.Errorf("enabled = %v, retry.max = %d", *cfg.Enabled, *cfg.Retry.Max)
+	}
+
+	var fields []string
+	for _, d := range got {
+		fields = append(fields, d.Field+"="+d.Value)
+	}
+	if strings.Join(fields, " ") != "Peers=a,b Timeout=5s" {
+		t.Errorf("defaults = %v", fields)
+	}
+}
+
+func TestDefaultsRejectPlainScalars(t *testing.T) {
+	type config struct {
+		Timeout time.Duration `default:"5s"`
+	}
+	_, err := applyDefaults(&config{})
+	if !errors.Is(err, ErrConfigDefault) {
+		t.Fatalf("err = %v, want ErrConfigDefault", err)
+	}
+	want := `invalid config default: Timeout default "5s": an explicit zero could not be told from an unset value, use *time.Duration`
+	if err.Error() != want {
+		t.Errorf("err = %q\nwant  %q", err, want)
+	}
+}
+
+func TestDefaultsFailAutoLoad(t *testing.T) {
+	type section struct {
+		Retries int `default:"3"`
+	}
+	type root struct {
+		ZzDefaults section
+	}
+
+	RegisterModule(ModuleDescriptor{
+		Name:       "zzDefaults",
+		ConfigType: section{},
+		New: func(aninterface.StaticData, any, aninterface.AnLogger) AnModule {
+			t.Fatal("module built despite an invalid default")
+			return nil
+		},
+	})
+	defer delete(moduleRegistry, "zzDefaults")
+
+	log := anlogtest.New()
+	m := NewAnWare(context.Background(), nil, log)
+	err := m.AutoLoadModules(nil, &root{ZzDefaults: section{Retries: 1}}, log)
+
+	var cerr *ConfigError
+	if !errors.As(err, &cerr) || cerr.Module != "zzDefaults" || !errors.Is(err, ErrConfigDefault) {
+		t.Fatalf("err = %v, want a zzDefaults ErrConfigDefault", err)
+	}
+	if !strings.Contains(err.Error(), "use *int") {
+		t.Errorf("err = %v, want the pointer hint", err)
+	}
+}
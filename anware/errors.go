@@ -0,0 +1,59 @@
+package anware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrConfigRoot         = errors.New("invalid config root")
+	ErrConfigMissing      = errors.New("config section missing")
+	ErrConfigTypeMismatch = errors.New("config type mismatch")
+	ErrConfigInherit      = errors.New("config inheritance failed")
+	ErrConfigDefault      = errors.New("invalid config default")
+)
+
+// MissingConfigPolicy decides what happens when a registered module has no
+// section in the application Config.
+type MissingConfigPolicy int
+
+const (
+	// MissingConfigFatal reports the module as a boot error (default).
+	MissingConfigFatal MissingConfigPolicy = iota
+	// MissingConfigWarn logs a warning and leaves the module disabled.
+	MissingConfigWarn
+)
+
+// ConfigError describes a configuration problem for a single module.
+type ConfigError struct {
+	Module string
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("module %s: %v", e.Module, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors aggregates the configuration errors of every module.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d config error(s): %s", len(e), strings.Join(msgs, "; "))
+}
+
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
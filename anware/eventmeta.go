@@ -0,0 +1,57 @@
+package anware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// Meta returns the event description used for log correlation.
+func (e AnWareEvent) Meta() aninterface.EventMeta {
+	return aninterface.EventMeta{
+		Source:        e.Source,
+		Target:        e.Target,
+		Type:          e.Type,
+		CorrelationID: e.CorrelationID,
+	}
+}
+
+// Context returns parent carrying the event metadata. Pass it to
+// AnLogger.WithContext to tag log lines, and to SendCtx / SendSyncCtx to
+// keep the correlation ID on the events sent while handling this one:
+//
+//	ctx := ev.Context(m.ctx)
+//	m.logger.WithContext(ctx).Info("handling")
+//	m.mw.SendCtx(ctx, anware.AnWareEvent{Source: m.Name(), Target: "anDb", Type: "get"})
+func (e AnWareEvent) Context(parent context.Context) context.Context {
+	if parent == nil {
+		parent = context.Background()
+	}
+	return aninterface.ContextWithEventMeta(parent, e.Meta())
+}
+
+// logFields is the event description for the AnWare logs (no payload).
+func (e AnWareEvent) logFields() []any {
+	return e.Meta().Fields()
+}
+
+func newCorrelationID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// withCorrelation fills an empty CorrelationID from ctx, or a new one.
+func withCorrelation(ctx context.Context, msg AnWareEvent) AnWareEvent {
+	if msg.CorrelationID != "" {
+		return msg
+	}
+	if meta, ok := aninterface.EventMetaFromContext(ctx); ok && meta.CorrelationID != "" {
+		msg.CorrelationID = meta.CorrelationID
+	} else {
+		msg.CorrelationID = newCorrelationID()
+	}
+	return msg
+}
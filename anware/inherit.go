@@ -0,0 +1,295 @@
+package anware
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Inheritance records a module config value that was filled from the root
+// Config.
+type Inheritance struct {
+	Field string // path inside the module config, e.g. Db.Host
+	From  string // root path, e.g. Database.Host
+	Value string // effective value after inheritance
+}
+
+// applyInheritance fills a module section from the root Config.
+//
+// A field inherits when it is tagged `inherit:"Root.Path"` or, for backward
+// compatibility, when its name ends in _Glob (source: the root field of the
+// same name without the suffix). Zero scalars are replaced, structs,
+// pointers and maps are deep-merged (module values win), empty slices are
+// copied, or extended with `inherit:"Root.Path,append"`. Nested structs of
+// the section are processed recursively. A type mismatch is an error for
+// tagged fields; legacy _Glob fields are then left untouched.
+func applyInheritance(root any, cfg any) ([]Inheritance, error) {
+	rootVal := reflect.ValueOf(root).Elem()
+	cfgVal := reflect.ValueOf(cfg).Elem()
+	if cfgVal.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var out []Inheritance
+	err := inheritStruct(rootVal, cfgVal, "", &out)
+	return out, err
+}
+
+func inheritStruct(root, dst reflect.Value, prefix string, out *[]Inheritance) error {
+	t := dst.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := dst.Field(i)
+		if !sf.IsExported() || !field.CanSet() {
+			continue
+		}
+		fieldPath := prefix + sf.Name
+
+		from, opts := parseTag(sf.Tag.Get("inherit"))
+		legacy := false
+		if from == "" && strings.HasSuffix(sf.Name, "_Glob") && len(sf.Name) > 5 {
+			from = strings.TrimSuffix(sf.Name, "_Glob")
+			legacy = true
+		}
+
+		if from != "" {
+			src, ok := lookupPath(root, from)
+			// legacy _Glob fields of another type are skipped, as they always were
+			if ok && legacy && src.Type() != field.Type() {
+				ok = false
+			}
+			if ok && !src.IsZero() {
+				if src.Type() != field.Type() {
+					return fmt.Errorf(
+						"%w: %s inherits %s: expected %s, got %s",
+						ErrConfigInherit,
+						fieldPath,
+						from,
+						field.Type(),
+						src.Type(),
+					)
+				}
+				if mergeValue(field, src, hasTagOption(opts, "append")) {
+					*out = append(*out, Inheritance{
+						Field: fieldPath,
+						From:  from,
+						Value: formatValue(field),
+					})
+				}
+			}
+		}
+
+		switch {
+		case field.Kind() == reflect.Struct:
+			if err := inheritStruct(root, field, fieldPath+".", out); err != nil {
+				return err
+			}
+		case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+			if err := inheritStruct(root, field.Elem(), fieldPath+".", out); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupPath resolves a dotted path from the root Config. Each segment
+// matches a field name, its json/anware tag name, or a string map key.
+func lookupPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, seg := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := fieldBySegment(v, seg)
+			if !ok {
+				return reflect.Value{}, false
+			}
+			v = f
+
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, false
+			}
+			e := v.MapIndex(reflect.ValueOf(seg).Convert(v.Type().Key()))
+			if !e.IsValid() {
+				return reflect.Value{}, false
+			}
+			v = e
+
+		default:
+			return reflect.Value{}, false
+		}
+	}
+	return v, true
+}
+
+func fieldBySegment(v reflect.Value, seg string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		jsonName, _ := parseTag(sf.Tag.Get("json"))
+		anName, _ := parseTag(sf.Tag.Get("anware"))
+		if sf.Name == seg || jsonName == seg || anName == seg {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// mergeValue merges src into dst, keeping what dst already defines. It
+// reports whether dst changed.
+func mergeValue(dst, src reflect.Value, appendSlice bool) bool {
+	if src.IsZero() {
+		return false
+	}
+	if dst.IsZero() {
+		dst.Set(deepCopy(src))
+		return true
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		changed := false
+		for i := 0; i < dst.NumField(); i++ {
+			if !dst.Field(i).CanSet() {
+				continue
+			}
+			if mergeValue(dst.Field(i), src.Field(i), appendSlice) {
+				changed = true
+			}
+		}
+		return changed
+
+	case reflect.Ptr:
+		// a non-nil *bool, *int... was set explicitly, even to its zero value
+		if dst.Elem().Kind() != reflect.Struct {
+			return false
+		}
+		return mergeValue(dst.Elem(), src.Elem(), appendSlice)
+
+	case reflect.Map:
+		changed := false
+		iter := src.MapRange()
+		for iter.Next() {
+			k, sv := iter.Key(), iter.Value()
+			dv := dst.MapIndex(k)
+			if !dv.IsValid() {
+				dst.SetMapIndex(k, deepCopy(sv))
+				changed = true
+				continue
+			}
+			// map values are not addressable: merge into a copy
+			merged := reflect.New(dv.Type()).Elem()
+			merged.Set(deepCopy(dv))
+			if mergeValue(merged, sv, appendSlice) {
+				dst.SetMapIndex(k, merged)
+				changed = true
+			}
+		}
+		return changed
+
+	case reflect.Slice:
+		if dst.Len() == 0 {
+			dst.Set(deepCopy(src))
+			return true
+		}
+		if appendSlice {
+			dst.Set(reflect.AppendSlice(dst, deepCopy(src)))
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatValue prints v like %v, but with the values pointers point to
+// instead of their addresses.
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		parts := make([]string, v.NumField())
+		for i := range parts {
+			if !v.Type().Field(i).IsExported() {
+				parts[i] = "?"
+				continue
+			}
+			parts[i] = formatValue(v.Field(i))
+		}
+		return "{" + strings.Join(parts, " ") + "}"
+	case reflect.Slice, reflect.Array:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i))
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
+
+// deepCopy copies maps, slices and pointers so that modules never share
+// mutable state with the root Config.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type().Elem())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return cp
+	}
+
+	return v
+}
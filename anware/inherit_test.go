@@ -0,0 +1,110 @@
+package anware
+
+import (
+	"errors"
+	"testing"
+)
+
+type inheritDb struct {
+	Host    string
+	Port    int
+	Enabled *bool
+}
+
+type inheritRoot struct {
+	Region   string
+	Port     int
+	Retries  *int
+	Enabled  *bool
+	Database inheritDb
+	Tags     []string
+}
+
+type inheritModule struct {
+	Region_Glob string
+	Port_Glob   string    // legacy, other type: skipped
+	Retries     *int      `inherit:"Retries"`
+	Enabled     *bool     `inherit:"Enabled"`
+	Db          inheritDb `inherit:"Database"`
+	Tags        []string  `inherit:"Tags,append"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestInheritance(t *testing.T) {
+	root := &inheritRoot{
+		Region:   "eu",
+		Port:     8080,
+		Retries:  ptr(3),
+		Enabled:  ptr(true),
+		Database: inheritDb{Host: "db", Port: 5432, Enabled: ptr(true)},
+		Tags:     []string{"root"},
+	}
+	cfg := &inheritModule{
+		Retries: ptr(0),
+		Enabled: ptr(false),
+		Db:      inheritDb{Port: 6543, Enabled: ptr(false)},
+		Tags:    []string{"mod"},
+	}
+
+	got, err := applyInheritance(root, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Region_Glob != "eu" {
+		t.Errorf("Region_Glob = %q, want eu", cfg.Region_Glob)
+	}
+	if cfg.Port_Glob != "" {
+		t.Errorf("Port_Glob = %q, want untouched", cfg.Port_Glob)
+	}
+	// explicit zero values behind pointers win
+	if *cfg.Retries != 0 || *cfg.Enabled || *cfg.Db.Enabled {
+		t.Errorf("explicit values overridden: retries=%d enabled=%v db.enabled=%v", *cfg.Retries, *cfg.Enabled, *cfg.Db.Enabled)
+	}
+	if cfg.Db.Host != "db" || cfg.Db.Port != 6543 {
+		t.Errorf("Db = %+v, want host from root and module port", cfg.Db)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "mod" || cfg.Tags[1] != "root" {
+		t.Errorf("Tags = %v", cfg.Tags)
+	}
+
+	fields := map[string]string{}
+	for _, in := range got {
+		fields[in.Field] = in.Value
+	}
+	if _, ok := fields["Retries"]; ok {
+		t.Errorf("Retries reported as inherited: %v", got)
+	}
+	if fields["Region_Glob"] != "eu" || fields["Db"] != "{db 6543 false}" {
+		t.Errorf("inherited = %v", got)
+	}
+}
+
+func TestInheritanceNilPointer(t *testing.T) {
+	root := &inheritRoot{Retries: ptr(3), Enabled: ptr(true)}
+	cfg := &inheritModule{}
+
+	got, err := applyInheritance(root, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Retries == nil || *cfg.Retries != 3 || cfg.Retries == root.Retries {
+		t.Errorf("Retries = %v, want a copy of 3", cfg.Retries)
+	}
+	for _, in := range got {
+		if in.Field == "Retries" && in.Value != "3" {
+			t.Errorf("Retries value = %q, want 3", in.Value)
+		}
+	}
+}
+
+func TestInheritanceTypeMismatch(t *testing.T) {
+	type module struct {
+		Port string `inherit:"Port"`
+	}
+	_, err := applyInheritance(&inheritRoot{Port: 80}, &module{})
+	if !errors.Is(err, ErrConfigInherit) {
+		t.Fatalf("err = %v, want ErrConfigInherit", err)
+	}
+}
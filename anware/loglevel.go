@@ -0,0 +1,102 @@
+package anware
+
+import (
+	"fmt"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// SetLogLevel is the payload of a "log_level" event sent to "anWare":
+//
+//	mw.Send(AnWareEvent{Target: "anWare", Type: "log_level",
+//		Data: anware.SetLogLevel{Module: "anTest", Level: "debug"}})
+//
+// An empty Module targets the core logger and every module without its own
+// level.
+type SetLogLevel struct {
+	Module string
+	Level  string
+}
+
+// WithModuleLogLevels gives the listed modules their own minimum log level.
+func WithModuleLogLevels(levels map[string]aninterface.LogLevel) Option {
+	return func(m *AnWare) {
+		for name, lvl := range levels {
+			m.logLevels[name] = lvl
+		}
+	}
+}
+
+// WithModuleLogFiles sends the logs of the listed modules to their own file
+// (see AnLogger.WithFile) instead of the shared per-level files.
+func WithModuleLogFiles(files map[string]string) Option {
+	return func(m *AnWare) {
+		for name, file := range files {
+			m.logFiles[name] = file
+		}
+	}
+}
+
+// moduleLogger returns the logger handed to a module: a child of the root
+// logger tagged with module=<name> and instance=<AnCoreID>, writing to the
+// module file if one is configured, with its own level so that it can be
+// changed independently.
+func (m *AnWare) moduleLogger(name, instance string, root aninterface.AnLogger) aninterface.AnLogger {
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+
+	level, ok := m.logLevels[name]
+	if !ok {
+		level = root.Level()
+	}
+
+	l := root
+	if file := m.logFiles[name]; file != "" {
+		l = l.WithFile(file)
+	}
+	l = l.With("module", name, "instance", instance).WithLevel(level)
+
+	m.loggers[name] = l
+	return l
+}
+
+// SetLogLevel changes a log level at runtime. module "" changes the core
+// logger and the modules that have no level of their own.
+func (m *AnWare) SetLogLevel(module string, level aninterface.LogLevel) error {
+	m.logMu.Lock()
+	defer m.logMu.Unlock()
+
+	if module == "" {
+		m.Logger.SetLevel(level)
+		for name, l := range m.loggers {
+			if _, own := m.logLevels[name]; !own {
+				l.SetLevel(level)
+			}
+		}
+		return nil
+	}
+
+	l, ok := m.loggers[module]
+	if !ok {
+		return fmt.Errorf("log level: unknown module %s", module)
+	}
+	m.logLevels[module] = level
+	l.SetLevel(level)
+	return nil
+}
+
+func (m *AnWare) handleSetLogLevel(msg AnWareEvent) error {
+	req, ok := msg.Data.(SetLogLevel)
+	if !ok {
+		return fmt.Errorf("log_level: expected anware.SetLogLevel, got %T", msg.Data)
+	}
+	level, err := aninterface.ParseLogLevel(req.Level)
+	if err != nil {
+		return err
+	}
+	if err := m.SetLogLevel(req.Module, level); err != nil {
+		return err
+	}
+	m.Logger.Info("[ANWARE] Log level changed", "module", req.Module, "level", level, "source", msg.Source)
+	return nil
+}
@@ -1,7 +1,9 @@
 package anware
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
 )
 
@@ -20,31 +22,77 @@ func (m *AnWare) Run() {
 			mod.Start()
 		}(mod, ch)
 
-		m.Logger.Info("[ANWARE] Module loaded: " + name)
+		m.Logger.Info("[ANWARE] Module loaded", "module", name)
 
 	}
 }
 
+// Shutdown stops the modules, the bridge, the bus and the recorders, then
+// closes the logger. Events sent afterwards are dropped. Only the first call
+// does the work; the others wait for it to end, so it is safe to call after
+// an "exit" event.
 func (m *AnWare) Shutdown() {
+	m.stopOnce.Do(m.shutdown)
+}
+
+func (m *AnWare) shutdown() {
 	m.Logger.Info("[ANWARE] Stopping AnWare...")
 
 	for _, mod := range m.mods {
 		if err := mod.Stop(); err != nil {
-			m.Logger.Error(fmt.Sprintf("[ANWARE] Error stopping module %s: %v", mod.Name(), err))
+			m.Logger.Error("[ANWARE] Error stopping module", "module", mod.Name(), "error", err)
 		}
 	}
 
+	// a main waiting on the context may return as soon as it is cancelled:
+	// write what is buffered first
+	if f, ok := m.Logger.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	// its readers feed the bus: stop them before closing it
+	if b := m.bridge.Load(); b != nil {
+		b.Close()
+	}
+
 	if m.cancel != nil {
 		m.cancel()
 	}
 
+	m.busMu.Lock()
+	m.busClosed = true
 	close(m.bus)
+	m.busMu.Unlock()
 	m.wg.Wait()
 	m.Logger.Info("[ANWARE] All modules stopped.")
+
+	m.stopRecorders()
+
+	// flush and close the log files (shared by the module loggers)
+	if c, ok := m.Logger.(io.Closer); ok {
+		c.Close()
+	}
 }
 
+// Broadcast sends msg to every module but its source, including the modules
+// of the processes linked by a Bridge.
 func (m *AnWare) Broadcast(msg AnWareEvent) {
-	msgCopy := msg
+	msg = withCorrelation(context.Background(), msg)
+	m.broadcast(msg, false)
+	if b := m.bridge.Load(); b != nil && m.intercept == nil {
+		b.broadcastOut(msg)
+	}
+}
+
+func (m *AnWare) broadcast(msg AnWareEvent, fanout bool) {
+	msgCopy := withCorrelation(context.Background(), msg)
+	msgCopy.fanout = fanout
+	if m.intercept != nil {
+		msgCopy.Target = "*"
+		m.intercept(msgCopy)
+		return
+	}
+
 	for name := range m.mods {
 		if name == msg.Source {
 			continue
@@ -56,10 +104,29 @@ func (m *AnWare) Broadcast(msg AnWareEvent) {
 }
 
 func (m *AnWare) Send(msg AnWareEvent) {
+	m.SendCtx(context.Background(), msg)
+}
+
+// SendCtx is Send keeping the correlation ID of the event carried by ctx
+// (see AnWareEvent.Context) when msg has none.
+func (m *AnWare) SendCtx(ctx context.Context, msg AnWareEvent) {
+	msg = withCorrelation(ctx, msg)
+	if m.intercept != nil {
+		m.intercept(msg)
+		return
+	}
+
+	m.busMu.RLock()
+	defer m.busMu.RUnlock()
+	if m.busClosed {
+		m.Logger.Debug("[ANWARE] AnWare stopped, event dropped", msg.logFields()...)
+		return
+	}
+
 	select {
 	case m.bus <- msg:
 	default:
-		m.Logger.Info(fmt.Sprintf("[ANWARE] Bus full, event dropped: %+v", msg))
+		m.Logger.Warn("[ANWARE] Bus full, event dropped", msg.logFields()...)
 	}
 }
 
@@ -69,10 +136,21 @@ func (m *AnWare) SendSync(
 	msgType string,
 	data any,
 ) (any, error) {
+	return m.SendSyncCtx(context.Background(), source, target, msgType, data)
+}
+
+// SendSyncCtx is SendSync keeping the correlation ID carried by ctx.
+func (m *AnWare) SendSyncCtx(
+	ctx context.Context,
+	source string,
+	target string,
+	msgType string,
+	data any,
+) (any, error) {
 
 	replyCh := make(chan AnWareReply, 1)
 
-	m.Send(AnWareEvent{
+	m.SendCtx(ctx, AnWareEvent{
 		Source:  source,
 		Target:  target,
 		Type:    msgType,
@@ -101,6 +179,7 @@ func (m *AnWare) dispatchLoop() {
 			if !ok {
 				return
 			}
+			m.observe(msg)
 			m.LoopOfAnWare(msg)
 			m.routeMessage(msg)
 		}
@@ -109,22 +188,43 @@ func (m *AnWare) dispatchLoop() {
 
 func (m *AnWare) LoopOfAnWare(msg AnWareEvent) {
 	if msg.Target == "anWare" {
-		if msg.Type == "exit" {
+		switch msg.Type {
+		case "exit":
 			m.Shutdown()
+		case "log_level":
+			err := m.handleSetLogLevel(msg)
+			if err != nil {
+				m.Logger.Error("[ANWARE] Cannot set log level", "error", err)
+			}
+			if msg.ReplyTo != nil {
+				msg.ReplyTo <- AnWareReply{Err: err}
+			}
 		}
 	}
 }
 
 func (m *AnWare) routeMessage(msg AnWareEvent) {
+	// handled by LoopOfAnWare
+	if msg.Target == "anWare" {
+		return
+	}
 
 	if msg.Target == "*" {
-		m.Broadcast(msg)
+		m.broadcast(msg, true)
+		if b := m.bridge.Load(); b != nil && !msg.fromBridge {
+			b.broadcastOut(msg)
+		}
 		return
 	}
 
 	targetCh, found := m.routes[msg.Target]
 	if !found {
-		m.Logger.Info(fmt.Sprintf("[ANWARE] No module found for target: %s", msg.Target))
+		// lives in another process?
+		if b := m.bridge.Load(); b != nil && b.forward(msg) {
+			return
+		}
+
+		m.Logger.Warn("[ANWARE] No module found for target", msg.logFields()...)
 
 		if msg.ReplyTo != nil {
 			msg.ReplyTo <- AnWareReply{
@@ -137,7 +237,7 @@ func (m *AnWare) routeMessage(msg AnWareEvent) {
 	select {
 	case targetCh <- msg:
 	default:
-		m.Logger.Info(fmt.Sprintf("[ANWARE] Channel full for %s, event ignored: %+v", msg.Target, msg))
+		m.Logger.Warn("[ANWARE] Channel full, event ignored", msg.logFields()...)
 
 		if msg.ReplyTo != nil {
 			msg.ReplyTo <- AnWareReply{
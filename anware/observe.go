@@ -0,0 +1,48 @@
+package anware
+
+// Observe calls fn with every event taken from the bus, before it is
+// handled or routed. fn runs on the dispatch goroutine: it must not block
+// nor call SendSync. The returned function removes the observer.
+//
+// The copies made when routing a Target "*" event are not observed; the
+// "*" event itself is.
+func (m *AnWare) Observe(fn func(AnWareEvent)) (remove func()) {
+	m.obsMu.Lock()
+	defer m.obsMu.Unlock()
+
+	id := m.obsNext
+	m.obsNext++
+	m.observers[id] = fn
+
+	return func() {
+		m.obsMu.Lock()
+		defer m.obsMu.Unlock()
+		delete(m.observers, id)
+	}
+}
+
+func (m *AnWare) observe(msg AnWareEvent) {
+	if msg.fanout {
+		return
+	}
+	m.obsMu.RLock()
+	defer m.obsMu.RUnlock()
+	for _, fn := range m.observers {
+		fn(msg)
+	}
+}
+
+func (m *AnWare) stopRecorders() {
+	m.obsMu.RLock()
+	recorders := make([]*Recorder, 0, len(m.recorders))
+	for r := range m.recorders {
+		recorders = append(recorders, r)
+	}
+	m.obsMu.RUnlock()
+
+	for _, r := range recorders {
+		if err := r.Stop(); err != nil {
+			m.Logger.Error("[ANWARE] Recording not saved", "error", err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package anware
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"unicode"
 )
 
@@ -16,70 +17,169 @@ func toPascalCase(s string) string {
 	return string(r)
 }
 
-func extractSubConfig(appConfig any, moduleName string, expectedType any) any {
-	return extractSubStruct(appConfig, moduleName, expectedType, "Config")
+// parseTag splits a struct tag value like `name,opt1,opt2`.
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
 }
 
-func extractSubStruct(root any, moduleName string, expectedType any, kind string) any {
-	if root == nil {
-		panic(fmt.Sprintf("[ANWARE] %s root is nil", kind))
+func hasTagOption(opts []string, opt string) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
 	}
+	return false
+}
 
-	rootVal := reflect.ValueOf(root)
-	if rootVal.Kind() == reflect.Ptr {
-		rootVal = rootVal.Elem()
+// isGroup reports whether a root field only groups module sections
+// (`anware:",group"`).
+func isGroup(sf reflect.StructField) bool {
+	_, opts := parseTag(sf.Tag.Get("anware"))
+	return sf.Type.Kind() == reflect.Struct && hasTagOption(opts, "group")
+}
+
+// section lookup strategies, tried in this order over the whole tree
+var sectionMatchers = []func(sf reflect.StructField, moduleName string) bool{
+	// explicit `anware:"moduleName"`
+	func(sf reflect.StructField, moduleName string) bool {
+		name, _ := parseTag(sf.Tag.Get("anware"))
+		return name != "" && name == moduleName
+	},
+	// `json:"moduleName"`
+	func(sf reflect.StructField, moduleName string) bool {
+		name, _ := parseTag(sf.Tag.Get("json"))
+		return name != "" && name != "-" && name == moduleName
+	},
+	// legacy: PascalCase field name
+	func(sf reflect.StructField, moduleName string) bool {
+		return sf.Name == toPascalCase(moduleName)
+	},
+}
+
+// findSection walks the root struct and its grouping structs and returns the
+// index path and dotted name of the field holding the module section.
+func findSection(rootType reflect.Type, moduleName string) ([]int, string, bool) {
+	for _, match := range sectionMatchers {
+		if index, path, ok := searchSection(rootType, moduleName, match, nil, rootType.Name()); ok {
+			return index, path, true
+		}
 	}
+	return nil, "", false
+}
 
-	if rootVal.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("[ANWARE] %s root must be a struct", kind))
+func searchSection(
+	t reflect.Type,
+	moduleName string,
+	match func(reflect.StructField, string) bool,
+	index []int,
+	path string,
+) ([]int, string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || isGroup(sf) {
+			continue
+		}
+		if match(sf, moduleName) {
+			return append(append([]int{}, index...), i), path + "." + sf.Name, true
+		}
 	}
 
-	fieldName := toPascalCase(moduleName)
-	field := rootVal.FieldByName(fieldName)
-	if !field.IsValid() {
-		panic(fmt.Sprintf(
-			"[ANWARE] %s missing for module '%s' (expected field %s.%s)",
-			kind,
-			moduleName,
-			rootVal.Type().Name(),
-			fieldName,
-		))
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || !isGroup(sf) {
+			continue
+		}
+		sub := append(append([]int{}, index...), i)
+		if found, p, ok := searchSection(sf.Type, moduleName, match, sub, path+"."+sf.Name); ok {
+			return found, p, true
+		}
 	}
 
-	expected := reflect.TypeOf(expectedType)
-	actual := field.Type()
+	return nil, "", false
+}
+
+// configSection is the module part of the application Config.
+type configSection struct {
+	Config  any    // pointer to the section, nil when absent
+	Path    string // e.g. Config.Services.AnTest
+	Pointer bool   // declared as *T: presence is meaningful
+
+	field reflect.Value // the *T field itself, for pointer sections
+}
+
+// Present is false when the section is declared as a pointer and left nil.
+func (s configSection) Present() bool {
+	return s.Config != nil
+}
 
-	if expected != actual {
-		panic(fmt.Sprintf(
-			"[ANWARE] %s type mismatch for module '%s': expected %s, got %s",
-			kind,
+// allocate sets a nil pointer section to a new zero value.
+func (s *configSection) allocate() {
+	if s.Present() || !s.field.IsValid() {
+		return
+	}
+	s.field.Set(reflect.New(s.field.Type().Elem()))
+	s.Config = s.field.Interface()
+}
+
+func extractSubConfig(appConfig any, moduleName string, expectedType any) (configSection, error) {
+	return extractSubStruct(appConfig, moduleName, expectedType, "Config")
+}
+
+func extractSubStruct(root any, moduleName string, expectedType any, kind string) (configSection, error) {
+	var sec configSection
+
+	if root == nil {
+		return sec, fmt.Errorf("%w: %s root is nil", ErrConfigRoot, kind)
+	}
+
+	rootVal := reflect.ValueOf(root)
+	if rootVal.Kind() != reflect.Ptr || rootVal.IsNil() || rootVal.Elem().Kind() != reflect.Struct {
+		return sec, fmt.Errorf("%w: %s root must be a pointer to a struct", ErrConfigRoot, kind)
+	}
+	rootVal = rootVal.Elem()
+
+	index, path, ok := findSection(rootVal.Type(), moduleName)
+	if !ok {
+		return sec, fmt.Errorf(
+			"%w: expected field %s.%s or a field tagged `anware:\"%s\"`",
+			ErrConfigMissing,
+			rootVal.Type().Name(),
+			toPascalCase(moduleName),
 			moduleName,
-			expected,
-			actual,
-		))
+		)
 	}
+	sec.Path = path
+	field := rootVal.FieldByIndex(index)
 
-	fieldPtr := field.Addr().Interface()
-	fieldVal := reflect.ValueOf(fieldPtr).Elem()
+	expected := reflect.TypeOf(expectedType)
+	if expected != nil && expected.Kind() == reflect.Ptr {
+		expected = expected.Elem()
+	}
+	actual := field.Type()
 
-	for i := 0; i < fieldVal.NumField(); i++ {
-		subField := fieldVal.Type().Field(i)
-		if subField.Name == "" {
-			continue
-		}
+	switch {
+	case actual == expected:
+		field = field.Addr()
 
-		if !fieldVal.Field(i).IsZero() {
-			continue
+	case actual.Kind() == reflect.Ptr && actual.Elem() == expected:
+		sec.Pointer = true
+		sec.field = field
+		if field.IsNil() {
+			return sec, nil
 		}
 
-		if len(subField.Name) > 5 && subField.Name[len(subField.Name)-5:] == "_Glob" {
-			globalName := subField.Name[:len(subField.Name)-5]
-			globalVal := rootVal.FieldByName(globalName)
-			if globalVal.IsValid() && globalVal.Type() == fieldVal.Field(i).Type() {
-				fieldVal.Field(i).Set(globalVal)
-			}
-		}
+	default:
+		return sec, fmt.Errorf(
+			"%w: %s expected %s or *%s, got %s",
+			ErrConfigTypeMismatch,
+			path,
+			expected,
+			expected,
+			actual,
+		)
 	}
+	sec.Config = field.Interface()
 
-	return field.Addr().Interface()
+	return sec, nil
 }
@@ -0,0 +1,281 @@
+package anware
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordedEvent is one line of a recording (JSON lines). The payload is
+// kept as JSON when the codec produces JSON, base64 otherwise.
+type RecordedEvent struct {
+	Time          time.Time       `json:"time"`
+	Source        string          `json:"source"`
+	Target        string          `json:"target"`
+	Type          string          `json:"type"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Sync          bool            `json:"sync,omitempty"` // sent with SendSync
+	Data          json.RawMessage `json:"data,omitempty"`
+	RawData       []byte          `json:"raw_data,omitempty"`
+	EncodeError   string          `json:"encode_error,omitempty"`
+}
+
+// payload returns the encoded payload, nil if there is none.
+func (r RecordedEvent) payload() []byte {
+	if len(r.Data) > 0 {
+		return r.Data
+	}
+	return r.RawData
+}
+
+// Event decodes rec back into an event (without ReplyTo).
+func (rec RecordedEvent) Event(codec Codec) (AnWareEvent, error) {
+	ev := AnWareEvent{
+		Source:        rec.Source,
+		Target:        rec.Target,
+		Type:          rec.Type,
+		CorrelationID: rec.CorrelationID,
+	}
+	if raw := rec.payload(); raw != nil {
+		data, err := codec.Decode(rec.Type, raw)
+		if err != nil {
+			return ev, fmt.Errorf("%s->%s %q: %w", rec.Source, rec.Target, rec.Type, err)
+		}
+		ev.Data = data
+	}
+	return ev, nil
+}
+
+// Recorder writes every event going through the bus to a recording.
+// Recorders still running are stopped by AnWare.Shutdown.
+type Recorder struct {
+	mw     *AnWare
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+	codec  Codec
+	remove func()
+	count  int
+	err    error
+}
+
+// Record starts recording the bus traffic to w with codec (DefaultCodecs
+// if nil) until Stop.
+func (m *AnWare) Record(w io.Writer, codec Codec) *Recorder {
+	if codec == nil {
+		codec = DefaultCodecs
+	}
+	r := &Recorder{mw: m, w: bufio.NewWriter(w), codec: codec}
+	r.remove = m.Observe(r.write)
+
+	m.obsMu.Lock()
+	m.recorders[r] = true
+	m.obsMu.Unlock()
+	return r
+}
+
+// RecordFile is Record to a new file at path.
+func (m *AnWare) RecordFile(path string, codec Codec) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r := m.Record(f, codec)
+	r.closer = f
+	return r, nil
+}
+
+// NewRecordedEvent serialises ev, stamped now, with codec.
+func NewRecordedEvent(ev AnWareEvent, codec Codec) RecordedEvent {
+	rec := RecordedEvent{
+		Time:          time.Now(),
+		Source:        ev.Source,
+		Target:        ev.Target,
+		Type:          ev.Type,
+		CorrelationID: ev.CorrelationID,
+		Sync:          ev.ReplyTo != nil,
+	}
+	if ev.Data != nil {
+		raw, err := codec.Encode(ev.Type, ev.Data)
+		switch {
+		case err != nil:
+			rec.EncodeError = err.Error()
+		case json.Valid(raw):
+			rec.Data = raw
+		default:
+			rec.RawData = raw
+		}
+	}
+	return rec
+}
+
+func (r *Recorder) write(ev AnWareEvent) {
+	line, err := json.Marshal(NewRecordedEvent(ev, r.codec))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		_, err = r.w.Write(append(line, '\n'))
+	}
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	r.count++
+}
+
+// Count returns the number of events recorded so far.
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Stop ends the recording, flushes it and closes the file of RecordFile.
+// It returns the first write error, if any.
+func (r *Recorder) Stop() error {
+	r.remove()
+
+	r.mw.obsMu.Lock()
+	delete(r.mw.recorders, r)
+	r.mw.obsMu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	errs := []error{r.err, r.w.Flush()}
+	if r.closer != nil {
+		errs = append(errs, r.closer.Close())
+		r.closer = nil
+	}
+	return errors.Join(errs...)
+}
+
+// LoadRecording reads a recording written by a Recorder.
+func LoadRecording(rd io.Reader) ([]RecordedEvent, error) {
+	var events []RecordedEvent
+	dec := json.NewDecoder(rd)
+	for {
+		var ev RecordedEvent
+		err := dec.Decode(&ev)
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return events, fmt.Errorf("recording event %d: %w", len(events)+1, err)
+		}
+		events = append(events, ev)
+	}
+}
+
+// LoadRecordingFile is LoadRecording from a file.
+func LoadRecordingFile(path string) ([]RecordedEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadRecording(f)
+}
+
+// Replayer sends recorded events back into an AnWare. SendSync events are
+// sent with a ReplyTo whose answer is discarded.
+type Replayer struct {
+	mw     *AnWare
+	events []RecordedEvent
+	codec  Codec
+	speed  float64
+	filter func(RecordedEvent) bool
+	pos    int
+}
+
+type ReplayOption func(*Replayer)
+
+// ReplaySpeed scales the recorded delays: 1 is the original timing
+// (default), 10 is ten times faster, 0 sends without waiting.
+func ReplaySpeed(speed float64) ReplayOption {
+	return func(r *Replayer) { r.speed = speed }
+}
+
+// ReplayFilter replays only the events for which keep returns true, e.g.
+// to leave out Target "anWare" control events such as "exit".
+func ReplayFilter(keep func(RecordedEvent) bool) ReplayOption {
+	return func(r *Replayer) { r.filter = keep }
+}
+
+// NewReplayer prepares events for replay into mw; codec (DefaultCodecs if
+// nil) must match the one used to record.
+func (m *AnWare) NewReplayer(events []RecordedEvent, codec Codec, opts ...ReplayOption) *Replayer {
+	if codec == nil {
+		codec = DefaultCodecs
+	}
+	r := &Replayer{mw: m, events: events, codec: codec, speed: 1}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Remaining returns the number of events not replayed yet.
+func (r *Replayer) Remaining() int {
+	return len(r.events) - r.pos
+}
+
+// Step replays the next event immediately (step-by-step mode). ok is false
+// once the recording is exhausted.
+func (r *Replayer) Step() (ev RecordedEvent, ok bool, err error) {
+	for r.pos < len(r.events) {
+		ev = r.events[r.pos]
+		r.pos++
+		if r.filter != nil && !r.filter(ev) {
+			continue
+		}
+		return ev, true, r.send(ev)
+	}
+	return RecordedEvent{}, false, nil
+}
+
+// Run replays the remaining events, waiting between them according to the
+// recorded times and the speed. It stops early when ctx is done.
+func (r *Replayer) Run(ctx context.Context) error {
+	var prev time.Time
+	for r.pos < len(r.events) {
+		next := r.events[r.pos]
+		r.pos++
+		if r.filter != nil && !r.filter(next) {
+			continue
+		}
+
+		if r.speed > 0 && !prev.IsZero() {
+			delay := time.Duration(float64(next.Time.Sub(prev)) / r.speed)
+			if delay > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(delay):
+				}
+			}
+		}
+		prev = next.Time
+
+		if err := r.send(next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Replayer) send(rec RecordedEvent) error {
+	ev, err := rec.Event(r.codec)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	if rec.Sync {
+		ev.ReplyTo = make(chan AnWareReply, 1)
+	}
+	r.mw.Send(ev)
+	return nil
+}
@@ -1,8 +1,8 @@
 package anware
 
 import (
-	"fmt"
-	"reflect"
+	"errors"
+	"sort"
 
 	"github.com/Aninetix/core/aninterface"
 )
@@ -17,6 +17,10 @@ type ModuleDescriptor struct {
 	) AnModule
 
 	ConfigType any
+
+	// Flags optionally points to a struct owned by the module; its fields are
+	// parsed as --<Name>.<flag> alongside the application Flags.
+	Flags any
 }
 
 type ConfigValidator interface {
@@ -70,39 +74,166 @@ func (g GenericModuleConstructor[F, C]) Build(local aninterface.StaticData, conf
 // 	}
 // }
 
+// AutoLoadModules instantiates every registered module that has a usable
+// config section. Config errors are collected across all modules and
+// returned together as ConfigErrors; no module is started in that case.
 func (m *AnWare) AutoLoadModules(
 	staticData aninterface.StaticData,
 	appConfig any,
 	logger aninterface.AnLogger,
-) {
-	for name, desc := range moduleRegistry {
+) error {
+	report, pending, errs := m.planModules(appConfig, logger)
+	m.report = report
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	instance := ""
+	if staticData != nil {
+		instance = staticData.AnCoreID()
+	}
+
+	for _, p := range pending {
+		m.routes[p.desc.Name] = make(chan AnWareEvent, 128)
+		m.mods[p.desc.Name] = p.desc.New(staticData, p.cfg, m.moduleLogger(p.desc.Name, instance, logger))
+
+		logger.Info("[ANWARE] Auto-loaded module", "module", p.desc.Name)
+	}
+
+	return nil
+}
 
-		cfg := extractSubConfig(appConfig, name, desc.ConfigType)
-		cfgVal := reflect.ValueOf(cfg)
+// CheckModules runs extraction, activation, inheritance, defaults and
+// Validate() for every registered module, like AutoLoadModules, but never
+// calls New: ModuleLoaded in the report means "would be loaded". The error
+// is the aggregated ConfigErrors, if any; rejected modules are only in the
+// report.
+func (m *AnWare) CheckModules(appConfig any, logger aninterface.AnLogger) (LoadReport, error) {
+	report, _, errs := m.planModules(appConfig, logger)
+	m.report = report
+
+	if len(errs) > 0 {
+		return report, errs
+	}
+	return report, nil
+}
+
+type pendingModule struct {
+	desc ModuleDescriptor
+	cfg  any
+}
+
+func (m *AnWare) planModules(appConfig any, logger aninterface.AnLogger) (LoadReport, []pendingModule, ConfigErrors) {
+	var errs ConfigErrors
+	var pending []pendingModule
+	report := LoadReport{}
 
-		if cfgVal.Kind() == reflect.Ptr {
-			cfgVal = cfgVal.Elem()
+	for name := range m.enable {
+		if _, ok := moduleRegistry[name]; !ok {
+			logger.Warn("[ANWARE] --enable: unknown module", "module", name)
 		}
+	}
+	for name := range m.disable {
+		if _, ok := moduleRegistry[name]; !ok {
+			logger.Warn("[ANWARE] --disable: unknown module", "module", name)
+		}
+	}
+
+	for _, name := range registeredNames() {
+		desc := moduleRegistry[name]
+
+		sec, err := extractSubConfig(appConfig, name, desc.ConfigType)
+		mr := ModuleReport{Name: name, Path: sec.Path}
 
-		if cfgVal.IsZero() {
-			fmt.Print("module disabled, config value not Set: " + name)
-			// logger.Info("module disabled (empty config): " + name)
+		if err != nil {
+			mr.Reason = err.Error()
+			if errors.Is(err, ErrConfigMissing) && m.missingConfig == MissingConfigWarn {
+				mr.State = ModuleDisabled
+				logger.Warn("[ANWARE] Module disabled", "module", name, "error", err)
+			} else {
+				mr.State = ModuleFailed
+				errs = append(errs, &ConfigError{Module: name, Err: err})
+			}
+			report.Modules = append(report.Modules, mr)
+			continue
+		}
+
+		// inherit first: a section only filled from the root Config (e.g.
+		// through _Glob fields) is not an empty one
+		present := sec.Present()
+		var inherited []Inheritance
+		if present {
+			inherited, err = applyInheritance(appConfig, sec.Config)
+		}
+
+		enabled, reason := m.moduleEnabled(name, &sec)
+		mr.Reason = reason
+		if !enabled {
+			mr.State = ModuleDisabled
+			logger.Info("[ANWARE] Module disabled", "module", name, "reason", reason)
+			report.Modules = append(report.Modules, mr)
+			continue
+		}
+		cfg := sec.Config
+		mr.Config = cfg
+
+		if !present {
+			// allocated by --enable
+			inherited, err = applyInheritance(appConfig, cfg)
+		}
+		mr.Inherited = inherited
+		if err != nil {
+			mr.State = ModuleFailed
+			mr.Reason = err.Error()
+			errs = append(errs, &ConfigError{Module: name, Err: err})
+			report.Modules = append(report.Modules, mr)
+			continue
+		}
+
+		defaults, err := applyDefaults(cfg)
+		mr.Defaults = defaults
+		if err != nil {
+			mr.State = ModuleFailed
+			mr.Reason = err.Error()
+			errs = append(errs, &ConfigError{Module: name, Err: err})
+			report.Modules = append(report.Modules, mr)
 			continue
 		}
 
 		if v, ok := cfg.(ConfigValidator); ok {
 			if err := v.Validate(); err != nil {
-				logger.Error(
-					fmt.Sprintf("[ANWARE] module %s disabled: invalid config: %v", name, err),
-				)
+				mr.State = ModuleRejected
+				mr.Reason = "invalid config: " + err.Error()
+				logger.Error("[ANWARE] Module disabled: invalid config", "module", name, "error", err)
+				report.Modules = append(report.Modules, mr)
 				continue
 			}
 		}
-		mod := desc.New(staticData, cfg, logger)
 
-		m.routes[name] = make(chan AnWareEvent, 128)
-		m.mods[name] = mod
+		mr.State = ModuleLoaded
+		report.Modules = append(report.Modules, mr)
+		pending = append(pending, pendingModule{desc: desc, cfg: cfg})
+	}
+
+	return report, pending, errs
+}
+
+// RegisteredModules returns the descriptors of all registered modules, in
+// name order.
+func RegisteredModules() []ModuleDescriptor {
+	descs := make([]ModuleDescriptor, 0, len(moduleRegistry))
+	for _, name := range registeredNames() {
+		descs = append(descs, moduleRegistry[name])
+	}
+	return descs
+}
 
-		logger.Info("[ANWARE] Auto-loaded module: " + name)
+func registeredNames() []string {
+	names := make([]string, 0, len(moduleRegistry))
+	for name := range moduleRegistry {
+		names = append(names, name)
 	}
+	sort.Strings(names)
+	return names
 }
@@ -0,0 +1,67 @@
+package anware
+
+import (
+	"fmt"
+	"io"
+)
+
+type ModuleState string
+
+const (
+	ModuleLoaded   ModuleState = "loaded"
+	ModuleDisabled ModuleState = "disabled"
+	ModuleRejected ModuleState = "rejected" // Validate() failed
+	ModuleFailed   ModuleState = "error"    // config section unusable
+)
+
+// ModuleReport is the auto-load outcome for one registered module.
+type ModuleReport struct {
+	Name   string
+	Path   string // config section, e.g. Config.AnTest
+	State  ModuleState
+	Reason string
+
+	Config    any            // effective config handed to New
+	Inherited []Inheritance  // values taken from the root Config
+	Defaults  []DefaultValue // values taken from `default` tags
+}
+
+// LoadReport lists what AutoLoadModules did with every registered module,
+// in name order.
+type LoadReport struct {
+	Modules []ModuleReport
+}
+
+func (r LoadReport) Count(state ModuleState) int {
+	n := 0
+	for _, mr := range r.Modules {
+		if mr.State == state {
+			n++
+		}
+	}
+	return n
+}
+
+func (r LoadReport) Print(w io.Writer) {
+	for _, mr := range r.Modules {
+		line := fmt.Sprintf("%-9s %s", mr.State, mr.Name)
+		if mr.Path != "" {
+			line += " (" + mr.Path + ")"
+		}
+		if mr.Reason != "" {
+			line += ": " + mr.Reason
+		}
+		fmt.Fprintln(w, line)
+		for _, in := range mr.Inherited {
+			fmt.Fprintf(w, "          %s = %s (from %s)\n", in.Field, in.Value, in.From)
+		}
+		for _, d := range mr.Defaults {
+			fmt.Fprintf(w, "          %s = %s (default)\n", d.Field, d.Value)
+		}
+	}
+}
+
+// LoadReport returns the report of the last AutoLoadModules call.
+func (m *AnWare) LoadReport() LoadReport {
+	return m.report
+}
@@ -0,0 +1,87 @@
+package anware
+
+import (
+	"reflect"
+)
+
+// WithModuleSwitches forces modules on or off regardless of their config,
+// typically from --enable / --disable. Disable wins over enable.
+func WithModuleSwitches(enable, disable []string) Option {
+	return func(m *AnWare) {
+		m.enable = toSet(enable)
+		m.disable = toSet(disable)
+	}
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
+
+// enabledField returns the `Enabled *bool` switch of a section (or the field
+// tagged `anware:"enabled"`), if it declares one.
+func enabledField(cfg any) (*bool, bool) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	boolPtr := reflect.TypeOf((*bool)(nil))
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name, _ := parseTag(sf.Tag.Get("anware"))
+		if name != "enabled" && (name != "" || sf.Name != "Enabled") {
+			continue
+		}
+		if sf.Type != boolPtr || !sf.IsExported() {
+			continue
+		}
+		return v.Field(i).Interface().(*bool), true
+	}
+	return nil, false
+}
+
+// moduleEnabled decides whether a module with an extracted section should be
+// built, and why. Precedence: --disable, --enable, config switch, pointer
+// section presence, then the legacy zero-value heuristic.
+func (m *AnWare) moduleEnabled(name string, sec *configSection) (bool, string) {
+	if m.disable[name] {
+		return false, "disabled by --disable"
+	}
+
+	if m.enable[name] {
+		if !sec.Present() {
+			sec.allocate()
+		}
+		return true, "enabled by --enable"
+	}
+
+	if !sec.Present() {
+		return false, "config section absent"
+	}
+
+	if sw, ok := enabledField(sec.Config); ok && sw != nil {
+		if *sw {
+			return true, "enabled in config"
+		}
+		return false, "disabled in config"
+	}
+
+	if sec.Pointer {
+		return true, ""
+	}
+
+	if reflect.ValueOf(sec.Config).Elem().IsZero() {
+		return false, "config value not set"
+	}
+
+	return true, ""
+}
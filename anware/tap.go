@@ -0,0 +1,132 @@
+package anware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// TapFilter selects events by source, target and type; empty fields match
+// any value.
+type TapFilter struct {
+	Source string
+	Target string
+	Type   string
+}
+
+func (f TapFilter) Match(ev AnWareEvent) bool {
+	return (f.Source == "" || f.Source == ev.Source) &&
+		(f.Target == "" || f.Target == ev.Target) &&
+		(f.Type == "" || f.Type == ev.Type)
+}
+
+// Tap receives a copy of the bus events matching its filter. It is not a
+// routing target and never slows delivery down: when C is full, events are
+// dropped and counted.
+type Tap struct {
+	C <-chan AnWareEvent
+
+	ch      chan AnWareEvent
+	remove  func()
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// Tap attaches a tap buffering up to buf events (at least 1). Close it when
+// done.
+func (m *AnWare) Tap(filter TapFilter, buf int) *Tap {
+	if buf < 1 {
+		buf = 1
+	}
+	t := &Tap{ch: make(chan AnWareEvent, buf)}
+	t.C = t.ch
+	t.remove = m.Observe(func(ev AnWareEvent) {
+		if !filter.Match(ev) {
+			return
+		}
+		select {
+		case t.ch <- ev:
+		default:
+			t.dropped.Add(1)
+		}
+	})
+	return t
+}
+
+// Dropped returns the number of events lost because C was full.
+func (t *Tap) Dropped() uint64 {
+	return t.dropped.Load()
+}
+
+// Close detaches the tap and closes C.
+func (t *Tap) Close() {
+	t.once.Do(func() {
+		t.remove()
+		close(t.ch)
+	})
+}
+
+// TapFunc calls fn, on its own goroutine, for every matching event. The
+// returned function detaches it.
+func (m *AnWare) TapFunc(filter TapFilter, fn func(AnWareEvent)) (stop func()) {
+	t := m.Tap(filter, 256)
+	go func() {
+		for ev := range t.C {
+			fn(ev)
+		}
+	}()
+	return t.Close
+}
+
+// TapHandler streams the bus events as JSON lines (RecordedEvent, payload
+// encoded with codec, DefaultCodecs if nil) until the client disconnects.
+// Query parameters source, target and type filter the events; buf sets the
+// tap buffer (default 256).
+//
+//	curl -N 'http://localhost:6060/debug/anware/tap?target=anDb'
+func (m *AnWare) TapHandler(codec Codec) http.Handler {
+	if codec == nil {
+		codec = DefaultCodecs
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		filter := TapFilter{
+			Source: q.Get("source"),
+			Target: q.Get("target"),
+			Type:   q.Get("type"),
+		}
+		buf, err := strconv.Atoi(q.Get("buf"))
+		if err != nil || buf < 1 {
+			buf = 256
+		}
+
+		t := m.Tap(filter, buf)
+		defer t.Close()
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		enc := json.NewEncoder(w)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-m.context.Done():
+				return
+			case ev := <-t.C:
+				if err := enc.Encode(NewRecordedEvent(ev, codec)); err != nil {
+					return
+				}
+				if flusher != nil {
+					flusher.Flush()
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,273 @@
+// Package anwaretest runs a single module on a fake AnWare: the module is
+// built from its ModuleDescriptor, receives events from a test-controlled
+// inbox, and everything it sends is captured instead of being routed.
+//
+//	h := anwaretest.New(t, mymod.Descriptor, &mymod.Config{Port: 8080})
+//	h.Respond("anDb", "get", func(ev anware.AnWareEvent) (any, error) { return row, nil })
+//	h.Start()
+//	h.Deliver(anware.AnWareEvent{Source: "anApi", Type: "fetch", Data: id})
+//	out := h.WaitSent(func(ev anware.AnWareEvent) bool { return ev.Type == "done" })
+//	h.Stop()
+package anwaretest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aninetix/core/anlogtest"
+	"github.com/Aninetix/core/anware"
+	"github.com/Aninetix/core/internal/anlocal"
+)
+
+// DefaultTimeout bounds Deliver, WaitSent and the Start/Stop checks.
+const DefaultTimeout = 2 * time.Second
+
+// Responder answers a SendSync call of the module.
+type Responder func(ev anware.AnWareEvent) (any, error)
+
+// Harness drives one module. Failures are reported on the testing.TB given
+// to New.
+type Harness struct {
+	tb      testing.TB
+	Module  anware.AnModule
+	Log     *anlogtest.Logger
+	AnWare  *anware.AnWare
+	Timeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	inbox  chan anware.AnWareEvent
+
+	mu         sync.Mutex
+	sent       []anware.AnWareEvent
+	notify     chan struct{}
+	responders map[[2]string]Responder // {target, type}; "" matches any
+	delivered  int
+
+	started bool
+	stopped bool
+	done    chan struct{} // closed when Start returns
+}
+
+// New builds the module with desc.New(staticData, cfg, logger). cfg is
+// passed as is, like the section AnWare would extract (defaults and
+// Validate are not applied). The module is stopped at the end of the test
+// if Stop was not called.
+func New(tb testing.TB, desc anware.ModuleDescriptor, cfg any) *Harness {
+	tb.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &Harness{
+		tb:         tb,
+		Log:        anlogtest.New(),
+		Timeout:    DefaultTimeout,
+		ctx:        ctx,
+		cancel:     cancel,
+		inbox:      make(chan anware.AnWareEvent, 128),
+		notify:     make(chan struct{}),
+		responders: map[[2]string]Responder{},
+		done:       make(chan struct{}),
+	}
+	h.AnWare = anware.NewAnWare(ctx, cancel, h.Log, anware.WithInterceptor(h.intercept))
+
+	h.Module = desc.New(anlocal.LoadStaticData(), cfg, h.Log.With("module", desc.Name))
+	if h.Module == nil {
+		tb.Fatalf("anwaretest: %s: New returned nil", desc.Name)
+	}
+	if got := h.Module.Name(); got != desc.Name {
+		tb.Errorf("anwaretest: Name() = %q, descriptor says %q", got, desc.Name)
+	}
+
+	tb.Cleanup(func() {
+		if h.started && !h.stopped {
+			h.Stop()
+		}
+		cancel()
+	})
+	return h
+}
+
+// Context is the context given to Param; it is cancelled by Stop.
+func (h *Harness) Context() context.Context { return h.ctx }
+
+// Start calls Param then runs Start in a goroutine, like AnWare.Run.
+func (h *Harness) Start() {
+	h.tb.Helper()
+	if h.started {
+		h.tb.Fatalf("anwaretest: %s started twice", h.Module.Name())
+	}
+	h.started = true
+
+	h.Module.Param(h.ctx, h.inbox, h.AnWare)
+	go func() {
+		defer close(h.done)
+		h.Module.Start()
+	}()
+}
+
+// Stop calls the module Stop, cancels the context and checks that Stop
+// succeeded and that Start returned within Timeout.
+func (h *Harness) Stop() {
+	h.tb.Helper()
+	if !h.started {
+		h.tb.Fatalf("anwaretest: %s stopped before Start", h.Module.Name())
+	}
+	if h.stopped {
+		return
+	}
+	h.stopped = true
+
+	if err := h.Module.Stop(); err != nil {
+		h.tb.Errorf("anwaretest: %s: Stop() = %v", h.Module.Name(), err)
+	}
+	h.cancel()
+
+	select {
+	case <-h.done:
+	case <-time.After(h.Timeout):
+		h.tb.Errorf("anwaretest: %s: Start did not return %s after Stop", h.Module.Name(), h.Timeout)
+	}
+}
+
+// Returned reports whether Start has returned.
+func (h *Harness) Returned() bool {
+	select {
+	case <-h.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Deliver puts ev in the module inbox. Target defaults to the module name,
+// Source to "test" and CorrelationID to "test-<n>".
+func (h *Harness) Deliver(ev anware.AnWareEvent) {
+	h.tb.Helper()
+	if ev.Target == "" {
+		ev.Target = h.Module.Name()
+	}
+	if ev.Source == "" {
+		ev.Source = "test"
+	}
+	if ev.CorrelationID == "" {
+		h.mu.Lock()
+		h.delivered++
+		ev.CorrelationID = fmt.Sprintf("test-%d", h.delivered)
+		h.mu.Unlock()
+	}
+
+	select {
+	case h.inbox <- ev:
+	case <-time.After(h.Timeout):
+		h.tb.Fatalf("anwaretest: %s: inbox full, event %q not delivered", h.Module.Name(), ev.Type)
+	}
+}
+
+// Request delivers ev with a ReplyTo channel and waits for the module
+// answer, like another module calling SendSync.
+func (h *Harness) Request(ev anware.AnWareEvent) (any, error) {
+	h.tb.Helper()
+	reply := make(chan anware.AnWareReply, 1)
+	ev.ReplyTo = reply
+	h.Deliver(ev)
+
+	select {
+	case r := <-reply:
+		return r.Data, r.Err
+	case <-time.After(h.Timeout):
+		h.tb.Fatalf("anwaretest: %s: no reply to %q after %s", h.Module.Name(), ev.Type, h.Timeout)
+		return nil, nil
+	}
+}
+
+// Respond answers the SendSync calls of the module to target with msgType.
+// An empty target or msgType matches any.
+func (h *Harness) Respond(target, msgType string, fn Responder) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.responders[[2]string{target, msgType}] = fn
+}
+
+// Sent returns what the module sent or broadcast (Target "*"), oldest
+// first. A SendSync without responder can still be answered through the
+// ReplyTo of its event.
+func (h *Harness) Sent() []anware.AnWareEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]anware.AnWareEvent(nil), h.sent...)
+}
+
+// WaitSent waits until the module sent an event matching match, or fails
+// the test after Timeout.
+func (h *Harness) WaitSent(match func(anware.AnWareEvent) bool) anware.AnWareEvent {
+	h.tb.Helper()
+	deadline := time.NewTimer(h.Timeout)
+	defer deadline.Stop()
+
+	for {
+		h.mu.Lock()
+		notify := h.notify
+		sent := h.sent
+		h.mu.Unlock()
+
+		for _, ev := range sent {
+			if match(ev) {
+				return ev
+			}
+		}
+
+		select {
+		case <-notify:
+		case <-deadline.C:
+			h.tb.Fatalf("anwaretest: %s: no matching event sent after %s (sent: %s)", h.Module.Name(), h.Timeout, describe(sent))
+			return anware.AnWareEvent{}
+		}
+	}
+}
+
+// intercept receives everything the module gives to the AnWare.
+func (h *Harness) intercept(ev anware.AnWareEvent) {
+	h.mu.Lock()
+	h.sent = append(h.sent, ev)
+	close(h.notify)
+	h.notify = make(chan struct{})
+	fn := h.responder(ev)
+	h.mu.Unlock()
+
+	if ev.ReplyTo == nil || fn == nil {
+		return
+	}
+	data, err := fn(ev)
+	ev.ReplyTo <- anware.AnWareReply{Data: data, Err: err}
+}
+
+func (h *Harness) responder(ev anware.AnWareEvent) Responder {
+	for _, key := range [][2]string{
+		{ev.Target, ev.Type},
+		{ev.Target, ""},
+		{"", ev.Type},
+		{"", ""},
+	} {
+		if fn, ok := h.responders[key]; ok {
+			return fn
+		}
+	}
+	return nil
+}
+
+func describe(events []anware.AnWareEvent) string {
+	if len(events) == 0 {
+		return "none"
+	}
+	s := ""
+	for i, ev := range events {
+		if i > 0 {
+			s += ", "
+		}
+		s += fmt.Sprintf("%s->%s %q", ev.Source, ev.Target, ev.Type)
+	}
+	return s
+}
@@ -4,110 +4,256 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
-	"strconv"
-	"time"
+	"strings"
+
+	"github.com/Aninetix/core/internal/helpers"
 )
 
-func ParseFlags(s any) error {
-	rv := reflect.ValueOf(s)
-	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
-		return errors.New("ParseFlags: argument must be pointer to struct")
+// ErrDefinition signale une struct de flags invalide (erreur de programmation),
+// par opposition à une erreur de ligne de commande.
+var ErrDefinition = errors.New("invalid flag definition")
+
+// ParseFlags enregistre chaque champ exporté de s (pointeur sur struct) comme
+// flag dans un FlagSet privé puis parse os.Args[1:] (ou WithArgs).
+//
+//   - struct imbriquée : flags pointés (--db.host), préfixe via le tag flag
+//   - slice : flag répétable ou liste séparée par des virgules
+//   - map[string]T : paires key=value, répétables ou séparées par des virgules
+//   - tout type implémentant encoding.TextUnmarshaler ou flag.Value
+//
+// Un flag peut aussi venir de l'environnement (tag env:"ANINETIX_DEBUG" ou
+// WithEnvPrefix) ; priorité : CLI > env > default.
+//
+// -h / --help retourne flag.ErrHelp après avoir affiché l'usage.
+func ParseFlags(s any, opts ...Option) error {
+	_, err := Parse(s, opts...)
+	return err
+}
+
+// Parse est ParseFlags qui retourne aussi les arguments restants : le parsing
+// s'arrête au premier argument qui n'est pas un flag (ex. une sous-commande).
+func Parse(s any, opts ...Option) ([]string, error) {
+	o := newOptions(opts)
+
+	fs, r, err := o.flagSet(s)
+	if err != nil {
+		return nil, err
 	}
-	st := rv.Elem()
-	typ := st.Type()
 
-	// map index -> *string (valeur temporaire de flag)
-	tmp := make(map[int]*string)
+	if err := fs.Parse(o.args); err != nil {
+		return nil, err
+	}
 
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
+	if err := applyEnv(r.fs, r.env); err != nil {
+		return nil, err
+	}
+	if err := validate(fs, r.constraints); err != nil {
+		return nil, err
+	}
+	return fs.Args(), nil
+}
 
-		// n'enregistrer que les champs exportés
-		if !field.IsExported() {
-			continue
+// PrintDefaults écrit la liste des flags de s (et des WithStruct) dans w,
+// sans parser ni modifier s.
+func PrintDefaults(w io.Writer, s any, opts ...Option) error {
+	o := newOptions(opts)
+
+	fresh := func(target any) any {
+		rv := reflect.ValueOf(target)
+		if rv.Kind() != reflect.Ptr || rv.IsNil() {
+			return target
 		}
+		return reflect.New(rv.Elem().Type()).Interface()
+	}
+	for i := range o.extra {
+		o.extra[i].target = fresh(o.extra[i].target)
+	}
 
-		name := field.Tag.Get("flag")
-		if name == "" {
-			// fallback : nom du champ en minuscules
-			name = lowerFirst(field.Name)
+	_, r, err := o.flagSet(fresh(s))
+	if err != nil {
+		return err
+	}
+	r.printDefaults(w)
+	return nil
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.name == "" && len(os.Args) > 0 {
+		o.name = os.Args[0]
+	}
+	if !o.argsSet && len(os.Args) > 0 {
+		o.args = os.Args[1:]
+	}
+	return o
+}
+
+// flagSet crée le FlagSet privé et y enregistre s puis les WithStruct.
+func (o options) flagSet(s any) (*flag.FlagSet, *registrar, error) {
+	fs := flag.NewFlagSet(o.name, flag.ContinueOnError)
+	if o.output != nil {
+		fs.SetOutput(o.output)
+	}
+	if o.usage != nil {
+		fs.Usage = o.usage
+	}
+
+	r := &registrar{fs: fs, opts: o}
+	if o.usage == nil {
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+			r.printDefaults(fs.Output())
 		}
-		def := field.Tag.Get("default")
-		usage := field.Tag.Get("usage")
+	}
+	if err := r.registerStruct(s, ""); err != nil {
+		return nil, nil, err
+	}
+	for _, e := range o.extra {
+		if err := r.registerStruct(e.target, e.prefix+"."); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, r, nil
+}
+
+type registrar struct {
+	fs          *flag.FlagSet
+	opts        options
+	env         []envBinding
+	constraints []constraint
+	docs        []flagDoc
+}
 
-		// register as string flag, we'll convert after Parse
-		ptr := flag.String(name, def, usage)
-		tmp[i] = ptr
+func (r *registrar) registerStruct(s any, prefix string) error {
+	rv := reflect.ValueOf(s)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: ParseFlags: argument must be pointer to struct", ErrDefinition)
 	}
+	return r.register(rv.Elem(), prefix)
+}
 
-	flag.Parse()
+// register déclare les champs de st dans fs, récursivement pour les structs.
+func (r *registrar) register(st reflect.Value, prefix string) error {
+	typ := st.Type()
 
-	// après Parse(), convertir et assigner
 	for i := 0; i < typ.NumField(); i++ {
 		field := typ.Field(i)
+
+		// n'enregistrer que les champs exportés
 		if !field.IsExported() {
 			continue
 		}
-		ptrStr, ok := tmp[i]
-		if !ok {
+
+		tag := field.Tag.Get("flag")
+		if tag == "-" {
 			continue
 		}
-		valStr := *ptrStr
 		fv := st.Field(i)
 
-		switch fv.Kind() {
-		case reflect.String:
-			fv.SetString(valStr)
+		// struct embarquée sans tag : ses champs sont au même niveau
+		if field.Anonymous && tag == "" && fv.Kind() == reflect.Struct && !helpers.IsTextType(fv.Type()) {
+			if err := r.register(fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			// fallback : nom du champ en minuscules
+			name = lowerFirst(field.Name)
+		}
+		name = prefix + name
 
-		case reflect.Bool:
-			b, err := strconv.ParseBool(valStr)
-			if err != nil {
-				return fmt.Errorf("flag %s: %w", field.Name, err)
+		value, nested := newValue(fv)
+		if nested {
+			if err := r.register(fv, name+"."); err != nil {
+				return err
 			}
-			fv.SetBool(b)
-
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			// handle time.Duration specially if tagged type is time.Duration
-			if field.Type == reflect.TypeOf(time.Duration(0)) {
-				d, err := time.ParseDuration(valStr)
-				if err != nil {
-					return fmt.Errorf("flag %s (duration): %w", field.Name, err)
-				}
-				fv.SetInt(int64(d))
-				continue
+			continue
+		}
+		if value == nil {
+			return fmt.Errorf("%w: flag %s: type %s non supportée", ErrDefinition, name, fv.Type())
+		}
+
+		if def := field.Tag.Get("default"); def != "" {
+			if err := value.Set(def); err != nil {
+				return fmt.Errorf("%w: flag %s (default): %v", ErrDefinition, name, err)
 			}
-			bits := fv.Type().Bits()
-			n, err := strconv.ParseInt(valStr, 10, bits)
-			if err != nil {
-				return fmt.Errorf("flag %s: %w", field.Name, err)
+			// la première valeur CLI remplace le défaut au lieu de s'y ajouter
+			switch v := value.(type) {
+			case *sliceValue:
+				v.touched = false
+			case *mapValue:
+				v.touched = false
 			}
-			fv.SetInt(n)
+		}
 
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			bits := fv.Type().Bits()
-			n, err := strconv.ParseUint(valStr, 10, bits)
-			if err != nil {
-				return fmt.Errorf("flag %s: %w", field.Name, err)
-			}
-			fv.SetUint(n)
+		usage := field.Tag.Get("usage")
+		if c, ok := newConstraint(name, fv, field.Tag); ok {
+			r.constraints = append(r.constraints, c)
+			usage = strings.TrimSpace(usage + " [" + c.describe() + "]")
+		}
+		if env := envName(field.Tag.Get("env"), name, r.opts.envPrefix); env != "" {
+			r.env = append(r.env, envBinding{flag: name, env: env})
+			usage = strings.TrimSpace(usage + " (env $" + env + ")")
+		}
 
-		case reflect.Float32, reflect.Float64:
-			bits := fv.Type().Bits()
-			f, err := strconv.ParseFloat(valStr, bits)
-			if err != nil {
-				return fmt.Errorf("flag %s: %w", field.Name, err)
-			}
-			fv.SetFloat(f)
+		if r.fs.Lookup(name) != nil {
+			return fmt.Errorf("%w: flag %s defined twice", ErrDefinition, name)
+		}
+		r.fs.Var(value, name, usage)
 
-		default:
-			return fmt.Errorf("flag %s: type %s non supportée", field.Name, fv.Kind())
+		doc := flagDoc{name: name, kind: kindName(fv), usage: usage}
+		if !fv.IsZero() {
+			doc.def = value.String()
 		}
+		r.docs = append(r.docs, doc)
 	}
 
 	return nil
 }
 
+// newValue choisit le flag.Value adapté au champ ; nested indique une struct
+// à aplatir.
+func newValue(fv reflect.Value) (flag.Value, bool) {
+	if v, ok := fv.Addr().Interface().(flag.Value); ok {
+		return v, false
+	}
+	if helpers.IsTextType(fv.Type()) {
+		return &fieldValue{v: fv}, false
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		return nil, true
+
+	case reflect.Slice:
+		return &sliceValue{v: fv}, false
+
+	case reflect.Map:
+		if fv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		return &mapValue{v: fv}, false
+
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return &fieldValue{v: fv}, false
+	}
+
+	return nil, false
+}
+
 // Helper: lowercase first letter (simple fallback)
 func lowerFirst(s string) string {
 	if s == "" {
@@ -0,0 +1,51 @@
+package anflags
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// envBinding lie un flag à la variable d'environnement qui l'alimente.
+type envBinding struct {
+	flag string
+	env  string
+}
+
+// envName retourne la variable qui alimente un flag : le tag env, sinon
+// PREFIX_NOM automatique si un préfixe est configuré. `env:"-"` désactive.
+func envName(tag, flagName, prefix string) string {
+	if tag == "-" {
+		return ""
+	}
+	if tag != "" {
+		return tag
+	}
+	if prefix == "" {
+		return ""
+	}
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(flagName)
+	return strings.ToUpper(prefix + "_" + name)
+}
+
+// applyEnv renseigne, après Parse, chaque flag absent de la ligne de
+// commande depuis sa variable d'environnement : CLI > env > default.
+func applyEnv(fs *flag.FlagSet, bindings []envBinding) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for _, b := range bindings {
+		if set[b.flag] {
+			continue
+		}
+		val, ok := os.LookupEnv(b.env)
+		if !ok {
+			continue
+		}
+		if err := fs.Set(b.flag, val); err != nil {
+			return fmt.Errorf("flag %s (env %s): %w", b.flag, b.env, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package anflags
+
+import "io"
+
+type options struct {
+	envPrefix string
+
+	name    string
+	args    []string
+	argsSet bool
+	output  io.Writer
+	usage   func()
+
+	extra []extraStruct
+}
+
+// extraStruct est une struct de flags supplémentaire, enregistrée sous un préfixe.
+type extraStruct struct {
+	prefix string
+	target any
+}
+
+type Option func(*options)
+
+// WithEnvPrefix lie chaque flag sans tag env explicite à PREFIX_NOM_DU_FLAG
+// (points et tirets deviennent des underscores, ex. db.host ->
+// ANINETIX_DB_HOST).
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) { o.envPrefix = prefix }
+}
+
+// WithArgs parse args au lieu de os.Args[1:].
+func WithArgs(args []string) Option {
+	return func(o *options) {
+		o.args = args
+		o.argsSet = true
+	}
+}
+
+// WithName fixe le nom du programme affiché dans l'usage.
+func WithName(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// WithOutput redirige l'usage et les messages d'erreur (os.Stderr par défaut).
+func WithOutput(w io.Writer) Option {
+	return func(o *options) { o.output = w }
+}
+
+// WithStruct enregistre les champs de target (pointeur sur struct) dans le
+// même FlagSet, chaque nom de flag préfixé par prefix + ".".
+func WithStruct(prefix string, target any) Option {
+	return func(o *options) { o.extra = append(o.extra, extraStruct{prefix: prefix, target: target}) }
+}
+
+// WithUsage remplace le message d'usage affiché sur -h ou en cas d'erreur.
+func WithUsage(fn func()) Option {
+	return func(o *options) { o.usage = fn }
+}
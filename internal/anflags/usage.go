@@ -0,0 +1,77 @@
+package anflags
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aninetix/core/internal/helpers"
+)
+
+// flagDoc décrit un flag pour le message d'usage.
+type flagDoc struct {
+	name  string
+	kind  string // type affiché après le nom, vide pour un bool
+	usage string
+	def   string // valeur par défaut, vide si valeur zéro
+}
+
+// printDefaults écrit les flags triés par nom, au format de flag.PrintDefaults
+// mais avec le type du champ comme placeholder :
+//
+//	-workers int
+//	    nombre de workers [min 1] (default 4)
+func (r *registrar) printDefaults(w io.Writer) {
+	docs := append([]flagDoc(nil), r.docs...)
+	sort.Slice(docs, func(i, j int) bool { return docs[i].name < docs[j].name })
+
+	for _, d := range docs {
+		line := "  -" + d.name
+		if d.kind != "" {
+			line += " " + d.kind
+		}
+
+		text := d.usage
+		if d.def != "" {
+			text += " (default " + d.def + ")"
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			line += "\n    \t" + strings.ReplaceAll(text, "\n", "\n    \t")
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// kindName retourne le placeholder d'usage d'un champ.
+func kindName(fv reflect.Value) string {
+	if _, ok := fv.Addr().Interface().(flag.Value); ok || helpers.IsTextType(fv.Type()) {
+		return "value"
+	}
+	if fv.Type() == durationType {
+		return "duration"
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		return ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "uint"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	}
+	return "value"
+}
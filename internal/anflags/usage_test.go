@@ -0,0 +1,75 @@
+package anflags
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+)
+
+type usageFlags struct {
+	Debug   bool              `flag:"debug" usage:"mode debug"`
+	Color   bool              `flag:"color" default:"true"`
+	Workers int               `flag:"workers" min:"1" default:"4" usage:"nombre de workers"`
+	Timeout time.Duration     `flag:"timeout" default:"5s"`
+	Ratio   float64           `flag:"ratio"`
+	Count   uint              `flag:"count"`
+	Name    string            `flag:"name" usage:"nom" env:"APP_NAME"`
+	Peers   []string          `flag:"peer" default:"a,b"`
+	Labels  map[string]string `flag:"label"`
+	Db      struct {
+		Host string `flag:"host" default:"localhost"`
+	} `flag:"db"`
+}
+
+const usageGolden = `  -color
+    	(default true)
+  -count uint
+  -db.host string
+    	(default localhost)
+  -debug
+    	mode debug
+  -label map
+  -name string
+    	nom (env $APP_NAME)
+  -peer list
+    	(default a,b)
+  -ratio float
+  -timeout duration
+    	(default 5s)
+  -workers int
+    	nombre de workers [min 1] (default 4)
+`
+
+func TestPrintDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintDefaults(&buf, &usageFlags{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != usageGolden {
+		t.Errorf("usage:\n%s\nwant:\n%s", got, usageGolden)
+	}
+}
+
+func TestHelpUsesPrintDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := Parse(&usageFlags{}, WithArgs([]string{"-h"}), WithName("app"), WithOutput(&buf))
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Fatalf("err = %v, want flag.ErrHelp", err)
+	}
+	if want := "Usage of app:\n" + usageGolden; buf.String() != want {
+		t.Errorf("help:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestBoolFlagWithoutValue(t *testing.T) {
+	var f usageFlags
+	args, err := Parse(&f, WithArgs([]string{"--debug", "--color=false", "--name", "x", "serve"}), WithOutput(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Debug || f.Color || f.Name != "x" || len(args) != 1 || args[0] != "serve" {
+		t.Errorf("flags = %+v, args = %v", f, args)
+	}
+}
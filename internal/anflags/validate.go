@@ -0,0 +1,190 @@
+package anflags
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Aninetix/core/internal/helpers"
+)
+
+// ErrInvalid est l'erreur de base des violations de contraintes.
+var ErrInvalid = errors.New("invalid flags")
+
+// ValidationError regroupe toutes les violations détectées après le parsing.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalid, strings.Join(e.Violations, "; "))
+}
+
+func (e *ValidationError) Unwrap() error { return ErrInvalid }
+
+// constraint porte les tags de validation d'un flag :
+//
+//	required:"true"          valeur non nulle (CLI, env ou default)
+//	enum:"a,b,c"             valeurs autorisées (chaque élément pour une slice)
+//	min:"1" max:"10"         bornes numériques (durées acceptées : "1s")
+//	path:"file|dir|exists"   le chemin doit exister (ignoré si vide)
+type constraint struct {
+	flag  string
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+func newConstraint(name string, fv reflect.Value, tag reflect.StructTag) (constraint, bool) {
+	for _, key := range []string{"required", "enum", "min", "max", "path"} {
+		if _, ok := tag.Lookup(key); ok {
+			return constraint{flag: name, value: fv, tag: tag}, true
+		}
+	}
+	return constraint{}, false
+}
+
+// describe résume les contraintes pour le message d'usage.
+func (c constraint) describe() string {
+	var parts []string
+	if c.tag.Get("required") == "true" {
+		parts = append(parts, "required")
+	}
+	if enum, ok := c.tag.Lookup("enum"); ok {
+		parts = append(parts, "one of "+strings.Join(helpers.SplitList(enum), "|"))
+	}
+	if lo, ok := c.tag.Lookup("min"); ok {
+		parts = append(parts, "min "+lo)
+	}
+	if hi, ok := c.tag.Lookup("max"); ok {
+		parts = append(parts, "max "+hi)
+	}
+	if kind, ok := c.tag.Lookup("path"); ok {
+		parts = append(parts, "existing "+kind)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// validate vérifie toutes les contraintes et retourne l'ensemble des
+// violations, après avoir affiché l'usage.
+func validate(fs *flag.FlagSet, constraints []constraint) error {
+	var violations []string
+	for _, c := range constraints {
+		violations = append(violations, c.check()...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	out := fs.Output()
+	for _, v := range violations {
+		fmt.Fprintln(out, v)
+	}
+	fs.Usage()
+
+	return &ValidationError{Violations: violations}
+}
+
+func (c constraint) check() []string {
+	var out []string
+	v := c.value
+
+	if c.tag.Get("required") == "true" && v.IsZero() {
+		return []string{fmt.Sprintf("flag -%s is required", c.flag)}
+	}
+
+	if enum, ok := c.tag.Lookup("enum"); ok && !v.IsZero() {
+		allowed := helpers.SplitList(enum)
+		for _, item := range elements(v) {
+			if !contains(allowed, item) {
+				out = append(out, fmt.Sprintf("flag -%s: %q not in [%s]", c.flag, item, strings.Join(allowed, ", ")))
+			}
+		}
+	}
+
+	for _, bound := range []string{"min", "max"} {
+		limit, ok := c.tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+		if msg := checkBound(c.flag, v, bound, limit); msg != "" {
+			out = append(out, msg)
+		}
+	}
+
+	if kind, ok := c.tag.Lookup("path"); ok && v.Kind() == reflect.String && v.String() != "" {
+		if msg := checkPath(c.flag, v.String(), kind); msg != "" {
+			out = append(out, msg)
+		}
+	}
+
+	return out
+}
+
+func elements(v reflect.Value) []string {
+	if v.Kind() == reflect.Slice {
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return items
+	}
+	return []string{fmt.Sprint(v.Interface())}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func checkBound(name string, v reflect.Value, bound, limit string) string {
+	lim := reflect.New(v.Type()).Elem()
+	if err := helpers.SetFromString(lim, limit); err != nil {
+		return fmt.Sprintf("flag -%s: invalid %s tag %q: %v", name, bound, limit, err)
+	}
+
+	var order int
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		order = cmp.Compare(v.Int(), lim.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		order = cmp.Compare(v.Uint(), lim.Uint())
+	case reflect.Float32, reflect.Float64:
+		order = cmp.Compare(v.Float(), lim.Float())
+	default:
+		return fmt.Sprintf("flag -%s: %s tag needs a numeric type, got %s", name, bound, v.Type())
+	}
+
+	if bound == "min" && order < 0 {
+		return fmt.Sprintf("flag -%s: %v is below min %s", name, v.Interface(), limit)
+	}
+	if bound == "max" && order > 0 {
+		return fmt.Sprintf("flag -%s: %v is above max %s", name, v.Interface(), limit)
+	}
+	return ""
+}
+
+func checkPath(name, path, kind string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Sprintf("flag -%s: %v", name, err)
+	}
+	switch kind {
+	case "file":
+		if info.IsDir() {
+			return fmt.Sprintf("flag -%s: %s is a directory, expected a file", name, path)
+		}
+	case "dir":
+		if !info.IsDir() {
+			return fmt.Sprintf("flag -%s: %s is not a directory", name, path)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package anflags
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Aninetix/core/internal/helpers"
+)
+
+// fieldValue lie un flag à un champ de struct de tout type compris par
+// helpers.SetFromString.
+type fieldValue struct {
+	v reflect.Value
+}
+
+func (f *fieldValue) Set(s string) error {
+	return helpers.SetFromString(f.v, s)
+}
+
+// IsBoolFlag permet --debug sans valeur pour un champ bool.
+func (f *fieldValue) IsBoolFlag() bool {
+	return f != nil && f.v.IsValid() && f.v.Kind() == reflect.Bool
+}
+
+func (f *fieldValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	return fmt.Sprint(f.v.Interface())
+}
+
+// sliceValue accepte les flags répétés (--peer a --peer b) comme les listes
+// séparées par des virgules (--peer a,b). La première valeur CLI remplace le
+// défaut au lieu de s'y ajouter.
+type sliceValue struct {
+	v       reflect.Value
+	touched bool
+}
+
+func (s *sliceValue) Set(str string) error {
+	items := reflect.New(s.v.Type()).Elem()
+	if err := helpers.SetFromString(items, str); err != nil {
+		return err
+	}
+	if !s.touched {
+		s.v.Set(reflect.MakeSlice(s.v.Type(), 0, items.Len()))
+		s.touched = true
+	}
+	s.v.Set(reflect.AppendSlice(s.v, items))
+	return nil
+}
+
+func (s *sliceValue) String() string {
+	if s == nil || !s.v.IsValid() {
+		return ""
+	}
+	parts := make([]string, s.v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(s.v.Index(i).Interface())
+	}
+	return strings.Join(parts, ",")
+}
+
+// mapValue accepte des paires key=value répétées ou séparées par des
+// virgules (--label a=1 --label b=2, ou --label a=1,b=2).
+type mapValue struct {
+	v       reflect.Value
+	touched bool
+}
+
+func (m *mapValue) Set(str string) error {
+	pairs := reflect.New(m.v.Type()).Elem()
+	if err := helpers.SetFromString(pairs, str); err != nil {
+		return err
+	}
+	if !m.touched || m.v.IsNil() {
+		m.v.Set(reflect.MakeMap(m.v.Type()))
+		m.touched = true
+	}
+	iter := pairs.MapRange()
+	for iter.Next() {
+		m.v.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return nil
+}
+
+func (m *mapValue) String() string {
+	if m == nil || !m.v.IsValid() {
+		return ""
+	}
+	parts := make([]string, 0, m.v.Len())
+	iter := m.v.MapRange()
+	for iter.Next() {
+		parts = append(parts, fmt.Sprintf("%v=%v", iter.Key().Interface(), iter.Value().Interface()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
@@ -1,51 +1,115 @@
 package anlogger
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync/atomic"
 	"time"
 
 	"github.com/Aninetix/core/aninterface"
 )
 
 type AnLoggerImpl struct {
-	dir      string
-	fileName string // optionnel
-	debugOn  bool
+	sink     aninterface.LogSink // partagé avec les clones
+	fileName string              // optionnel, pour le sink fichier
+	level    *atomic.Int32
+	attrs    []slog.Attr // champs ajoutés par With
+	exit     func(code int)
 }
 
 var _ aninterface.AnLogger = (*AnLoggerImpl)(nil)
 
-// ---- constructeur principal ----
-func NewLogger(logDir string, debugOn bool) aninterface.AnLogger {
-	os.MkdirAll(logDir, 0755)
+// config regroupe les options des loggers et des sinks.
+type config struct {
+	format       Format
+	rotation     Rotation
+	fileRotation map[string]Rotation // par fileName (WithFile)
+	color        bool
+	sinks        []aninterface.LogSink
+	sampling     Sampling
+	exit         func(code int)
+}
+
+// Option configure un logger ou un sink à la construction.
+type Option func(*config)
+
+func newConfig(opts []Option) config {
+	c := config{format: FormatText, fileRotation: map[string]Rotation{}, exit: os.Exit}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithFormat choisit l'encodage des lignes (text par défaut).
+func WithFormat(f Format) Option {
+	return func(c *config) { c.format = f }
+}
+
+// WithSinks remplace le sink fichier par défaut ; plusieurs sinks sont
+// alimentés en parallèle (fan-out).
+func WithSinks(sinks ...aninterface.LogSink) Option {
+	return func(c *config) { c.sinks = append(c.sinks, sinks...) }
+}
+
+// WithExit remplace os.Exit appelé par Fatal (tests).
+func WithExit(exit func(code int)) Option {
+	return func(c *config) { c.exit = exit }
+}
 
-	return &AnLoggerImpl{
-		dir:     logDir,
-		debugOn: debugOn,
+// ---- constructeur principal ----
+func NewLogger(logDir string, debugOn bool, opts ...Option) aninterface.AnLogger {
+	level := aninterface.LevelInfo
+	if debugOn {
+		level = aninterface.LevelDebug
 	}
+	return NewLevelLogger(logDir, level, opts...)
 }
 
-// ---- génère automatiquement YYYY-MM-DD-type.log si aucun fileName ----
-func (l *AnLoggerImpl) getFilePath(t string) string {
-	if l.fileName != "" {
-		return filepath.Join(l.dir, l.fileName)
+// ---- constructeur avec niveau minimum explicite ----
+// Sans WithSinks, le logger écrit dans logDir (YYYY-MM-DD-level.log).
+func NewLevelLogger(logDir string, level aninterface.LogLevel, opts ...Option) aninterface.AnLogger {
+	c := newConfig(opts)
+
+	var sink aninterface.LogSink
+	switch len(c.sinks) {
+	case 0:
+		sink = NewFileSink(logDir, opts...)
+	case 1:
+		sink = c.sinks[0]
+	default:
+		sink = NewMultiSink(c.sinks...)
 	}
-	date := time.Now().Format("2006-01-02")
-	return filepath.Join(l.dir, fmt.Sprintf("%s-%s.log", date, t))
+	if c.sampling != (Sampling{}) {
+		sink = newSampler(sink, c.sampling)
+	}
+	return NewSinkLogger(sink, level, opts...)
 }
 
-// ---- crée un writer ----
-func (l *AnLoggerImpl) writerFor(t string) *log.Logger {
-	filePath := l.getFilePath(t)
-	f, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		panic(err)
+// ---- constructeur sur un sink quelconque (options : WithExit) ----
+func NewSinkLogger(sink aninterface.LogSink, level aninterface.LogLevel, opts ...Option) aninterface.AnLogger {
+	c := newConfig(opts)
+	l := &AnLoggerImpl{
+		sink:  sink,
+		level: new(atomic.Int32),
+		exit:  c.exit,
 	}
-	return log.New(f, "", log.LstdFlags)
+	l.level.Store(int32(level))
+	return l
+}
+
+// Flush vide les buffers des sinks.
+func (l *AnLoggerImpl) Flush() error {
+	return l.sink.Flush()
+}
+
+// Close vide et ferme les sinks du logger et de tous ses clones.
+func (l *AnLoggerImpl) Close() error {
+	return l.sink.Close()
 }
 
 // ---- file:line ----
@@ -57,27 +121,74 @@ func callerInfo() string {
 	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
 }
 
-// ---- Logs ----
-func (l *AnLoggerImpl) Info(msg string) {
-	l.writerFor("info").Printf("[INFO]  [%s] %s", callerInfo(), msg)
+// ---- niveau ----
+func (l *AnLoggerImpl) Level() aninterface.LogLevel {
+	return aninterface.LogLevel(l.level.Load())
 }
 
-func (l *AnLoggerImpl) Error(msg string) {
-	l.writerFor("error").Printf("[ERROR] [%s] %s", callerInfo(), msg)
+func (l *AnLoggerImpl) SetLevel(level aninterface.LogLevel) {
+	l.level.Store(int32(level))
 }
 
-func (l *AnLoggerImpl) Debug(msg string) {
-	if !l.debugOn {
+func (l *AnLoggerImpl) enabled(level aninterface.LogLevel) bool {
+	return level >= l.Level()
+}
+
+// ---- écriture commune : une entrée transmise au sink ----
+func (l *AnLoggerImpl) log(level aninterface.LogLevel, msg string, fields []any) {
+	if !l.enabled(level) {
 		return
 	}
-	l.writerFor("debug").Printf("[DEBUG] [%s] %s", callerInfo(), msg)
+	l.sink.WriteEntry(&aninterface.LogEntry{
+		Time:   time.Now(),
+		Level:  level,
+		Caller: callerInfo(),
+		Msg:    msg,
+		Fields: append(l.attrs[:len(l.attrs):len(l.attrs)], toAttrs(fields)...),
+		File:   l.fileName,
+	})
 }
 
-// ---- clone pour usage custom ----
-func (l *AnLoggerImpl) WithFile(filename string) aninterface.AnLogger {
-	return &AnLoggerImpl{
-		dir:      l.dir,
-		fileName: filename,
-		debugOn:  l.debugOn,
+// ---- Logs ----
+func (l *AnLoggerImpl) Trace(msg string, fields ...any) { l.log(aninterface.LevelTrace, msg, fields) }
+func (l *AnLoggerImpl) Debug(msg string, fields ...any) { l.log(aninterface.LevelDebug, msg, fields) }
+func (l *AnLoggerImpl) Info(msg string, fields ...any)  { l.log(aninterface.LevelInfo, msg, fields) }
+func (l *AnLoggerImpl) Warn(msg string, fields ...any)  { l.log(aninterface.LevelWarn, msg, fields) }
+func (l *AnLoggerImpl) Error(msg string, fields ...any) { l.log(aninterface.LevelError, msg, fields) }
+
+func (l *AnLoggerImpl) Fatal(msg string, fields ...any) {
+	l.log(aninterface.LevelFatal, msg, fields)
+	l.Close()
+	l.exit(1)
+}
+
+// ---- clone partageant le niveau, avec des champs en plus ----
+func (l *AnLoggerImpl) With(fields ...any) aninterface.AnLogger {
+	c := *l
+	c.attrs = append(l.attrs[:len(l.attrs):len(l.attrs)], toAttrs(fields)...)
+	return &c
+}
+
+// ---- clone portant source, target, type et correlation_id de l'évènement ----
+func (l *AnLoggerImpl) WithContext(ctx context.Context) aninterface.AnLogger {
+	meta, ok := aninterface.EventMetaFromContext(ctx)
+	if !ok {
+		return l
 	}
+	return l.With(meta.Fields()...)
+}
+
+// ---- clone pour usage custom : seul le sink fichier change de fichier ----
+func (l *AnLoggerImpl) WithFile(filename string) aninterface.AnLogger {
+	c := *l
+	c.fileName = filename
+	return &c
+}
+
+// ---- clone avec son propre niveau ----
+func (l *AnLoggerImpl) WithLevel(level aninterface.LogLevel) aninterface.AnLogger {
+	c := *l
+	c.level = new(atomic.Int32)
+	c.level.Store(int32(level))
+	return &c
 }
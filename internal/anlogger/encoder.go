@@ -0,0 +1,143 @@
+package anlogger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// Format est l'encodage d'une ligne de log.
+type Format string
+
+const (
+	FormatText   Format = "text"   // 2006/01/02 15:04:05 [INFO]  [file.go:12] msg k=v
+	FormatJSON   Format = "json"   // une ligne JSON par entrée
+	FormatLogfmt Format = "logfmt" // time=... level=INFO caller=file.go:12 msg=... k=v
+)
+
+// ParseFormat accepte text, json et logfmt ("" = text).
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatText, nil
+	case FormatText, FormatJSON, FormatLogfmt:
+		return f, nil
+	}
+	return FormatText, fmt.Errorf("unknown log format %q", s)
+}
+
+// encodeOptions adapte l'encodage au sink.
+type encodeOptions struct {
+	time  bool // horodatage (syslog a le sien)
+	color bool // couleur ANSI du niveau, format text uniquement
+}
+
+// couleurs ANSI par niveau
+var levelColors = map[aninterface.LogLevel]string{
+	aninterface.LevelTrace: "\033[90m",
+	aninterface.LevelDebug: "\033[36m",
+	aninterface.LevelInfo:  "\033[32m",
+	aninterface.LevelWarn:  "\033[33m",
+	aninterface.LevelError: "\033[31m",
+	aninterface.LevelFatal: "\033[1;31m",
+}
+
+// toAttrs normalise les champs clé/valeur à la manière de slog (clé sans
+// valeur -> !BADKEY).
+func toAttrs(fields []any) []slog.Attr {
+	if len(fields) == 0 {
+		return nil
+	}
+	var r slog.Record
+	r.Add(fields...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return attrs
+}
+
+// ---- encode une entrée, terminée par un saut de ligne ----
+func (f Format) encode(buf *bytes.Buffer, e *aninterface.LogEntry, o encodeOptions) {
+	switch f {
+	case FormatJSON:
+		handle(slog.NewJSONHandler(buf, handlerOptions(o)), e)
+	case FormatLogfmt:
+		handle(slog.NewTextHandler(buf, handlerOptions(o)), e)
+	default:
+		if o.time {
+			buf.WriteString(e.Time.Format("2006/01/02 15:04:05 "))
+		}
+		level := fmt.Sprintf("%-7s", "["+e.Level.String()+"]")
+		if color, ok := levelColors[e.Level]; ok && o.color {
+			level = color + level + "\033[0m"
+		}
+		fmt.Fprintf(buf, "%s [%s] %s", level, e.Caller, e.Msg)
+		for _, a := range e.Fields {
+			appendText(buf, "", a)
+		}
+		buf.WriteByte('\n')
+	}
+}
+
+// handlerOptions garde nos noms de niveaux (TRACE, FATAL) au lieu de
+// DEBUG-4 / ERROR+4.
+func handlerOptions(o encodeOptions) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level: slog.Level(aninterface.LevelTrace),
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				if !o.time {
+					return slog.Attr{}
+				}
+			case slog.LevelKey:
+				if lvl, ok := a.Value.Any().(slog.Level); ok {
+					return slog.String(slog.LevelKey, aninterface.LogLevel(lvl).String())
+				}
+			}
+			return a
+		},
+	}
+}
+
+func handle(h slog.Handler, e *aninterface.LogEntry) {
+	r := slog.NewRecord(e.Time, slog.Level(e.Level), e.Msg, 0)
+	r.AddAttrs(slog.String("caller", e.Caller))
+	r.AddAttrs(e.Fields...)
+	h.Handle(context.Background(), r)
+}
+
+// ---- k=v, groupes aplatis en a.b=v ----
+func appendText(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			appendText(buf, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(prefix + a.Key)
+	buf.WriteByte('=')
+	s := v.String()
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		s = strconv.Quote(s)
+	}
+	buf.WriteString(s)
+}
@@ -0,0 +1,162 @@
+package anlogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rotation règle la rotation et la rétention d'un flux de log. Valeur zéro :
+// pas de rotation, rien n'est supprimé (comportement historique).
+type Rotation struct {
+	MaxSize  int64         // octets ; au-delà le fichier est renommé
+	Interval time.Duration // durée maximale d'un même fichier
+	MaxAge   time.Duration // les fichiers tournés plus vieux sont supprimés
+	MaxFiles int           // nombre maximum de fichiers tournés conservés
+	Compress bool          // gzip des fichiers tournés
+}
+
+func (r Rotation) enabled() bool {
+	return r != Rotation{}
+}
+
+// WithRotation applique r à tous les flux du sink fichier.
+func WithRotation(r Rotation) Option {
+	return func(c *config) { c.rotation = r }
+}
+
+// WithFileRotation applique r aux loggers WithFile(name), à la place de la
+// rotation générale.
+func WithFileRotation(name string, r Rotation) Option {
+	return func(c *config) { c.fileRotation[name] = r }
+}
+
+// ---- rotation applicable à un flux (niveau ou fileName) ----
+func (s *fileStore) rotationFor(stream string, custom bool) Rotation {
+	if custom {
+		if r, ok := s.fileRotation[stream]; ok {
+			return r
+		}
+	}
+	return s.rotation
+}
+
+// ---- faut-il tourner avant d'écrire n octets ? ----
+func (of *openFile) needsRotation(r Rotation, n int, now time.Time) bool {
+	if r.MaxSize > 0 && of.size > 0 && of.size+int64(n) > r.MaxSize {
+		return true
+	}
+	return r.Interval > 0 && now.Sub(of.opened) >= r.Interval
+}
+
+// backupName : app.log -> app.20060102-150405.000.log, ou
+// app.20060102-150405.000.N.log si ce nom (ou son .gz) est déjà pris, par
+// exemple par deux rotations dans la même milliseconde.
+func backupName(path string, now time.Time) string {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext) + "." + now.Format("20060102-150405.000")
+
+	name := base + ext
+	for i := 1; exists(name) || exists(name+".gz"); i++ {
+		name = fmt.Sprintf("%s.%d%s", base, i, ext)
+	}
+	return name
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// ---- renomme le fichier courant (déjà fermé) ----
+func rotateFile(path string, now time.Time) (string, error) {
+	backup := backupName(path, now)
+	if err := os.Rename(path, backup); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// cleanup compresse les fichiers tournés puis applique MaxAge et MaxFiles à
+// tous les fichiers du flux sauf le courant. pattern couvre aussi les
+// fichiers des jours précédents (YYYY-MM-DD-info.log).
+func cleanup(pattern, current string, r Rotation) {
+	matches, _ := filepath.Glob(pattern)
+
+	type backup struct {
+		path string
+		mod  time.Time
+	}
+	var backups []backup
+	for _, p := range matches {
+		if p == current {
+			continue
+		}
+		if r.Compress && !strings.HasSuffix(p, ".gz") {
+			if gz, err := compressFile(p); err == nil {
+				p = gz
+			}
+		}
+		info, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{p, info.ModTime()})
+	}
+
+	// plus récents d'abord
+	sort.Slice(backups, func(i, j int) bool { return backups[i].mod.After(backups[j].mod) })
+
+	now := time.Now()
+	for i, b := range backups {
+		tooOld := r.MaxAge > 0 && now.Sub(b.mod) > r.MaxAge
+		tooMany := r.MaxFiles > 0 && i >= r.MaxFiles
+		if tooOld || tooMany {
+			os.Remove(b.path)
+		}
+	}
+}
+
+// ---- path -> path.gz, en gardant la date de modification ----
+func compressFile(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	gzPath := path + ".gz"
+	out, err := os.OpenFile(gzPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", err
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(gzPath)
+		return "", err
+	}
+
+	os.Chtimes(gzPath, info.ModTime(), info.ModTime())
+	in.Close()
+	return gzPath, os.Remove(path)
+}
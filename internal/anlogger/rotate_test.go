@@ -0,0 +1,108 @@
+package anlogger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackupNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	first := backupName(path, now)
+	if want := filepath.Join(dir, "app.20261019-120000.000.log"); first != want {
+		t.Fatalf("backupName = %s, want %s", first, want)
+	}
+	os.WriteFile(first, nil, 0644)
+	os.WriteFile(filepath.Join(dir, "app.20261019-120000.000.1.log.gz"), nil, 0644)
+
+	if got, want := backupName(path, now), filepath.Join(dir, "app.20261019-120000.000.2.log"); got != want {
+		t.Fatalf("backupName after collisions = %s, want %s", got, want)
+	}
+}
+
+// readLines lit toutes les lignes des fichiers du répertoire, .gz compris.
+func readLines(t *testing.T, dir string) (lines []string, files []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		files = append(files, e.Name())
+		f, err := os.Open(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var r io.Reader = f
+		if strings.HasSuffix(e.Name(), ".gz") {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				t.Fatalf("%s: %v", e.Name(), err)
+			}
+			r = zr
+		}
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		f.Close()
+	}
+	return lines, files
+}
+
+func TestSizeRotationKeepsEveryLine(t *testing.T) {
+	dir := t.TempDir()
+	root := NewLogger(dir, false, WithFormat(FormatJSON), WithRotation(Rotation{MaxSize: 200}))
+	log := root.WithFile("app.log")
+
+	// bien plus d'une rotation par milliseconde
+	const n = 200
+	for i := 0; i < n; i++ {
+		log.Info(fmt.Sprintf("line %03d", i))
+	}
+	root.(io.Closer).Close()
+
+	lines, files := readLines(t, dir)
+	if len(files) < 10 {
+		t.Fatalf("only %d files: %v", len(files), files)
+	}
+	if len(lines) != n {
+		t.Fatalf("%d lines across %d files, want %d", len(lines), len(files), n)
+	}
+}
+
+func TestRetentionAndCompression(t *testing.T) {
+	dir := t.TempDir()
+	root := NewLogger(dir, false, WithRotation(Rotation{MaxSize: 200, MaxFiles: 3, Compress: true}))
+	log := root.WithFile("app.log")
+
+	for i := 0; i < 100; i++ {
+		log.Info(fmt.Sprintf("line %03d", i))
+	}
+	root.(io.Closer).Close()
+
+	_, files := readLines(t, dir)
+	var current, gz int
+	for _, name := range files {
+		switch {
+		case name == "app.log":
+			current++
+		case strings.HasSuffix(name, ".log.gz"):
+			gz++
+		default:
+			t.Errorf("unexpected file %s", name)
+		}
+	}
+	if current != 1 || gz != 3 {
+		t.Fatalf("files = %v, want app.log and 3 compressed backups", files)
+	}
+}
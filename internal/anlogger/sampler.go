@@ -0,0 +1,182 @@
+package anlogger
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// Sampling limite les messages répétés : par intervalle et par clé
+// (fichier WithFile + niveau + message), les First premières entrées
+// passent, puis une sur Thereafter (0 : plus aucune). À la fin de chaque
+// intervalle, une entrée "suppressed N messages" résume ce qui a été écarté,
+// dans le même fichier. Les ERROR et FATAL passent toujours ; First et
+// Thereafter à 0 valent First = 1.
+type Sampling struct {
+	Interval   time.Duration
+	First      int
+	Thereafter int
+}
+
+// WithSampling active l'échantillonnage sur tous les sinks du logger.
+func WithSampling(s Sampling) Option {
+	return func(c *config) { c.sampling = s }
+}
+
+// sampleKey : un budget par fichier cible (WithFile), niveau et message.
+type sampleKey struct {
+	file  string
+	level aninterface.LogLevel
+	msg   string
+}
+
+type sampleCount struct {
+	n          int
+	suppressed int
+}
+
+// sampler est un sink qui filtre avant de transmettre à inner.
+type sampler struct {
+	inner aninterface.LogSink
+	cfg   Sampling
+
+	mu      sync.Mutex
+	counts  map[sampleKey]*sampleCount
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var _ aninterface.LogSink = (*sampler)(nil)
+
+func newSampler(inner aninterface.LogSink, cfg Sampling) *sampler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Second
+	}
+	// sinon plus rien ne passerait
+	if cfg.First <= 0 && cfg.Thereafter <= 0 {
+		cfg.First = 1
+	}
+	return &sampler{inner: inner, cfg: cfg, counts: map[sampleKey]*sampleCount{}}
+}
+
+func (s *sampler) WriteEntry(e *aninterface.LogEntry) error {
+	if e.Level >= aninterface.LevelError {
+		return s.inner.WriteEntry(e)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return s.inner.WriteEntry(e)
+	}
+	s.startTicker()
+
+	key := sampleKey{e.File, e.Level, e.Msg}
+	c := s.counts[key]
+	if c == nil {
+		c = &sampleCount{}
+		s.counts[key] = c
+	}
+	c.n++
+	extra := c.n - s.cfg.First
+	pass := extra <= 0 || (s.cfg.Thereafter > 0 && extra%s.cfg.Thereafter == 0)
+	if !pass {
+		c.suppressed++
+	}
+	s.mu.Unlock()
+
+	if !pass {
+		return nil
+	}
+	return s.inner.WriteEntry(e)
+}
+
+// ---- démarré à la première entrée ----
+func (s *sampler) startTicker() {
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	go func() {
+		defer close(s.stopped)
+		t := time.NewTicker(s.cfg.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				s.summarize()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// summarize termine l'intervalle : remet les compteurs à zéro et écrit une
+// entrée par clé ayant perdu des messages.
+func (s *sampler) summarize() {
+	s.mu.Lock()
+	counts := s.counts
+	s.counts = map[sampleKey]*sampleCount{}
+	s.mu.Unlock()
+
+	keys := make([]sampleKey, 0, len(counts))
+	for k, c := range counts {
+		if c.suppressed > 0 {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].file != keys[j].file {
+			return keys[i].file < keys[j].file
+		}
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+
+	for _, k := range keys {
+		n := counts[k].suppressed
+		s.inner.WriteEntry(&aninterface.LogEntry{
+			Time:   time.Now(),
+			Level:  k.level,
+			Caller: "anlogger",
+			Msg:    fmt.Sprintf("suppressed %d messages", n),
+			File:   k.file,
+			Fields: []slog.Attr{
+				slog.String("msg", k.msg),
+				slog.Int("suppressed", n),
+				slog.Duration("interval", s.cfg.Interval),
+			},
+		})
+	}
+}
+
+func (s *sampler) Flush() error { return s.inner.Flush() }
+
+// Close écrit le dernier résumé puis ferme inner.
+func (s *sampler) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	stop := s.stop
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-s.stopped
+	}
+	s.summarize()
+	return s.inner.Close()
+}
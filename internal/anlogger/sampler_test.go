@@ -0,0 +1,117 @@
+package anlogger
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// memSink garde les entrées en mémoire.
+type memSink struct {
+	mu      sync.Mutex
+	entries []aninterface.LogEntry
+}
+
+func (m *memSink) WriteEntry(e *aninterface.LogEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, *e)
+	return nil
+}
+
+func (m *memSink) Flush() error { return nil }
+func (m *memSink) Close() error { return nil }
+
+func (m *memSink) count(file string, level aninterface.LogLevel, msg string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, e := range m.entries {
+		if e.File == file && e.Level == level && e.Msg == msg {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSampler(t *testing.T) {
+	mem := &memSink{}
+	s := newSampler(mem, Sampling{Interval: time.Hour, First: 2, Thereafter: 3})
+
+	write := func(file string, level aninterface.LogLevel, msg string, n int) {
+		for i := 0; i < n; i++ {
+			s.WriteEntry(&aninterface.LogEntry{Time: time.Now(), Level: level, Msg: msg, File: file})
+		}
+	}
+	write("", aninterface.LevelInfo, "hot", 10)
+	write("audit.log", aninterface.LevelInfo, "hot", 4)
+	write("", aninterface.LevelError, "boom", 10)
+	write("", aninterface.LevelFatal, "dead", 5)
+	s.Close()
+
+	// 2 premières, puis une sur 3 : entrées 1, 2, 5 et 8
+	if got := mem.count("", aninterface.LevelInfo, "hot"); got != 4 {
+		t.Errorf("hot: %d entries, want 4", got)
+	}
+	// budget propre : entrées 1 et 2, pas 3 ni 4
+	if got := mem.count("audit.log", aninterface.LevelInfo, "hot"); got != 2 {
+		t.Errorf("audit.log hot: %d entries, want 2", got)
+	}
+	// jamais échantillonnées
+	if got := mem.count("", aninterface.LevelError, "boom"); got != 10 {
+		t.Errorf("boom: %d entries, want 10", got)
+	}
+	if got := mem.count("", aninterface.LevelFatal, "dead"); got != 5 {
+		t.Errorf("dead: %d entries, want 5", got)
+	}
+
+	// les résumés vont dans le fichier des entrées écartées
+	if got := mem.count("", aninterface.LevelInfo, "suppressed 6 messages"); got != 1 {
+		t.Errorf("missing summary for hot")
+	}
+	if got := mem.count("audit.log", aninterface.LevelInfo, "suppressed 2 messages"); got != 1 {
+		t.Errorf("missing audit.log summary for hot")
+	}
+	if got := mem.count("", aninterface.LevelError, "suppressed 0 messages"); got != 0 {
+		t.Errorf("unexpected summary for errors")
+	}
+}
+
+func TestSamplerZeroConfigLetsFirstThrough(t *testing.T) {
+	mem := &memSink{}
+	s := newSampler(mem, Sampling{Interval: time.Hour})
+	for i := 0; i < 3; i++ {
+		s.WriteEntry(&aninterface.LogEntry{Level: aninterface.LevelInfo, Msg: "x"})
+	}
+	s.Close()
+	if got := mem.count("", aninterface.LevelInfo, "x"); got != 1 {
+		t.Errorf("%d entries, want 1", got)
+	}
+}
+
+func TestSamplerSummaryInTargetFile(t *testing.T) {
+	dir := t.TempDir()
+	root := NewLogger(dir, false, WithSampling(Sampling{Interval: time.Hour, First: 1}))
+	audit := root.WithFile("audit.log")
+	for i := 0; i < 3; i++ {
+		audit.Warn("retry")
+	}
+	root.(io.Closer).Close()
+
+	data, err := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "suppressed 2 messages") {
+		t.Errorf("audit.log has no summary:\n%s", data)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*-warn.log")); len(files) != 0 {
+		t.Errorf("summary written to %v", files)
+	}
+}
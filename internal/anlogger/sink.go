@@ -0,0 +1,80 @@
+package anlogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// ConsoleSink écrit chaque entrée directement sur w (os.Stdout, os.Stderr).
+// Options : WithFormat, WithColor.
+type ConsoleSink struct {
+	mu   sync.Mutex
+	w    io.Writer
+	opts encodeOptions
+	fmt  Format
+}
+
+var _ aninterface.LogSink = (*ConsoleSink)(nil)
+
+// WithColor colorise le niveau des sinks console au format text.
+func WithColor(on bool) Option {
+	return func(c *config) { c.color = on }
+}
+
+func NewConsoleSink(w io.Writer, opts ...Option) *ConsoleSink {
+	c := newConfig(opts)
+	return &ConsoleSink{
+		w:    w,
+		fmt:  c.format,
+		opts: encodeOptions{time: true, color: c.color && c.format == FormatText},
+	}
+}
+
+func (s *ConsoleSink) WriteEntry(e *aninterface.LogEntry) error {
+	var buf bytes.Buffer
+	s.fmt.encode(&buf, e, s.opts)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+func (s *ConsoleSink) Flush() error { return nil }
+func (s *ConsoleSink) Close() error { return nil }
+
+// ---- fan-out vers plusieurs sinks ----
+type multiSink []aninterface.LogSink
+
+// NewMultiSink envoie chaque entrée à tous les sinks.
+func NewMultiSink(sinks ...aninterface.LogSink) aninterface.LogSink {
+	return multiSink(sinks)
+}
+
+func (m multiSink) WriteEntry(e *aninterface.LogEntry) error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.WriteEntry(e))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiSink) Flush() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiSink) Close() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, s.Close())
+	}
+	return errors.Join(errs...)
+}
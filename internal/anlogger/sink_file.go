@@ -0,0 +1,75 @@
+package anlogger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// FileSink écrit dans un répertoire : YYYY-MM-DD-level.log par niveau, ou le
+// fichier donné à WithFile. Options : WithFormat, WithRotation,
+// WithFileRotation.
+type FileSink struct {
+	dir    string
+	format Format
+	files  *fileStore
+}
+
+var _ aninterface.LogSink = (*FileSink)(nil)
+
+func NewFileSink(dir string, opts ...Option) *FileSink {
+	c := newConfig(opts)
+	os.MkdirAll(dir, 0755)
+
+	files := newFileStore()
+	files.rotation = c.rotation
+	files.fileRotation = c.fileRotation
+
+	return &FileSink{dir: dir, format: c.format, files: files}
+}
+
+// ---- génère automatiquement YYYY-MM-DD-type.log si aucun fileName ----
+func (s *FileSink) getFilePath(fileName, t string) string {
+	if fileName != "" {
+		return filepath.Join(s.dir, fileName)
+	}
+	date := time.Now().Format("2006-01-02")
+	return filepath.Join(s.dir, fmt.Sprintf("%s-%s.log", date, t))
+}
+
+// ---- flux, fichier courant et fichiers tournés ----
+func (s *FileSink) target(e *aninterface.LogEntry) target {
+	if e.File != "" {
+		ext := filepath.Ext(e.File)
+		stem := strings.TrimSuffix(e.File, ext)
+		return target{
+			stream:  e.File,
+			path:    s.getFilePath(e.File, ""),
+			pattern: filepath.Join(s.dir, stem+".*"+ext+"*"),
+			custom:  true,
+		}
+	}
+	t := strings.ToLower(e.Level.String())
+	return target{
+		stream:  t,
+		path:    s.getFilePath("", t),
+		pattern: filepath.Join(s.dir, "????-??-??-"+t+"*.log*"),
+	}
+}
+
+// WriteEntry écrit immédiatement les lignes ERROR et FATAL, les autres au
+// plus tard après une seconde.
+func (s *FileSink) WriteEntry(e *aninterface.LogEntry) error {
+	var buf bytes.Buffer
+	s.format.encode(&buf, e, encodeOptions{time: true})
+	s.files.write(s.target(e), buf.Bytes(), e.Level >= aninterface.LevelError)
+	return nil
+}
+
+func (s *FileSink) Flush() error { return s.files.Flush() }
+func (s *FileSink) Close() error { return s.files.Close() }
@@ -0,0 +1,50 @@
+//go:build !windows && !plan9
+
+package anlogger
+
+import (
+	"bytes"
+	"log/syslog"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// SyslogSink envoie les entrées à syslog, sans horodatage (syslog a le
+// sien). network et addr vides : socket syslog locale.
+type SyslogSink struct {
+	w   *syslog.Writer
+	fmt Format
+}
+
+var _ aninterface.LogSink = (*SyslogSink)(nil)
+
+func NewSyslogSink(network, addr, tag string, opts ...Option) (aninterface.LogSink, error) {
+	c := newConfig(opts)
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w, fmt: c.format}, nil
+}
+
+func (s *SyslogSink) WriteEntry(e *aninterface.LogEntry) error {
+	var buf bytes.Buffer
+	s.fmt.encode(&buf, e, encodeOptions{})
+	msg := buf.String()
+
+	switch {
+	case e.Level >= aninterface.LevelFatal:
+		return s.w.Crit(msg)
+	case e.Level >= aninterface.LevelError:
+		return s.w.Err(msg)
+	case e.Level >= aninterface.LevelWarn:
+		return s.w.Warning(msg)
+	case e.Level >= aninterface.LevelInfo:
+		return s.w.Info(msg)
+	default:
+		return s.w.Debug(msg)
+	}
+}
+
+func (s *SyslogSink) Flush() error { return nil }
+func (s *SyslogSink) Close() error { return s.w.Close() }
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+
+package anlogger
+
+import (
+	"errors"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// NewSyslogSink n'est pas disponible sur cette plateforme.
+func NewSyslogSink(network, addr, tag string, opts ...Option) (aninterface.LogSink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
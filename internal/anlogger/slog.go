@@ -0,0 +1,88 @@
+package anlogger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/Aninetix/core/aninterface"
+)
+
+// SlogLogger adapte un slog.Handler en AnLogger : les entrées, leurs champs
+// et la source (file:line de l'appelant) sont confiés au handler.
+type SlogLogger struct {
+	handler slog.Handler
+	level   *atomic.Int32
+}
+
+var _ aninterface.AnLogger = (*SlogLogger)(nil)
+
+// ---- constructeur ----
+func NewSlogLogger(h slog.Handler, level aninterface.LogLevel) aninterface.AnLogger {
+	l := &SlogLogger{handler: h, level: new(atomic.Int32)}
+	l.level.Store(int32(level))
+	return l
+}
+
+// ---- niveau ----
+func (l *SlogLogger) Level() aninterface.LogLevel {
+	return aninterface.LogLevel(l.level.Load())
+}
+
+func (l *SlogLogger) SetLevel(level aninterface.LogLevel) {
+	l.level.Store(int32(level))
+}
+
+// ---- écriture commune ----
+func (l *SlogLogger) log(level aninterface.LogLevel, msg string, fields []any) {
+	ctx := context.Background()
+	if level < l.Level() || !l.handler.Enabled(ctx, slog.Level(level)) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // Callers, log, Info
+	r := slog.NewRecord(time.Now(), slog.Level(level), msg, pcs[0])
+	r.Add(fields...)
+	l.handler.Handle(ctx, r)
+}
+
+// ---- Logs ----
+func (l *SlogLogger) Trace(msg string, fields ...any) { l.log(aninterface.LevelTrace, msg, fields) }
+func (l *SlogLogger) Debug(msg string, fields ...any) { l.log(aninterface.LevelDebug, msg, fields) }
+func (l *SlogLogger) Info(msg string, fields ...any)  { l.log(aninterface.LevelInfo, msg, fields) }
+func (l *SlogLogger) Warn(msg string, fields ...any)  { l.log(aninterface.LevelWarn, msg, fields) }
+func (l *SlogLogger) Error(msg string, fields ...any) { l.log(aninterface.LevelError, msg, fields) }
+
+func (l *SlogLogger) Fatal(msg string, fields ...any) {
+	l.log(aninterface.LevelFatal, msg, fields)
+	os.Exit(1)
+}
+
+// ---- clones ----
+func (l *SlogLogger) With(fields ...any) aninterface.AnLogger {
+	return &SlogLogger{handler: l.handler.WithAttrs(toAttrs(fields)), level: l.level}
+}
+
+// ---- clone portant source, target, type et correlation_id de l'évènement ----
+func (l *SlogLogger) WithContext(ctx context.Context) aninterface.AnLogger {
+	meta, ok := aninterface.EventMetaFromContext(ctx)
+	if !ok {
+		return l
+	}
+	return l.With(meta.Fields()...)
+}
+
+// WithFile n'a pas de fichier à changer côté slog : le nom est ajouté en
+// champ "log_file" pour que le handler puisse l'exploiter.
+func (l *SlogLogger) WithFile(filename string) aninterface.AnLogger {
+	return &SlogLogger{handler: l.handler.WithAttrs([]slog.Attr{slog.String("log_file", filename)}), level: l.level}
+}
+
+func (l *SlogLogger) WithLevel(level aninterface.LogLevel) aninterface.AnLogger {
+	c := &SlogLogger{handler: l.handler, level: new(atomic.Int32)}
+	c.level.Store(int32(level))
+	return c
+}
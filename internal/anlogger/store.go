@@ -0,0 +1,192 @@
+package anlogger
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// flushInterval est la période de vidage des buffers.
+const flushInterval = time.Second
+
+// fileStore garde les fichiers de log ouverts, un par flux (niveau ou
+// fileName), partagé par un logger et tous ses clones. Les écritures sont
+// bufferisées et vidées périodiquement, sur Flush et sur Close.
+type fileStore struct {
+	mu      sync.Mutex
+	files   map[string]*openFile // clé : flux (info, error, custom.log...)
+	closed  bool
+	stop    chan struct{}
+	stopped chan struct{}
+
+	rotation     Rotation
+	fileRotation map[string]Rotation // par fileName (WithFile)
+	cleanMu      sync.Mutex          // une seule passe de nettoyage à la fois
+	cleaning     sync.WaitGroup
+}
+
+type openFile struct {
+	path   string
+	f      *os.File
+	w      *bufio.Writer
+	size   int64
+	opened time.Time
+}
+
+// target désigne le fichier courant d'un flux et l'ensemble de ses
+// fichiers (pattern) pour la rétention.
+type target struct {
+	stream  string
+	path    string
+	pattern string
+	custom  bool // fileName posé par WithFile
+}
+
+func newFileStore() *fileStore {
+	return &fileStore{
+		files:        map[string]*openFile{},
+		fileRotation: map[string]Rotation{},
+	}
+}
+
+// ---- écrit une ligne dans le fichier courant du flux ----
+func (s *fileStore) write(t target, line []byte, flush bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// après Close : écriture directe, sans garder le fichier
+	if s.closed {
+		f, err := os.OpenFile(t.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			panic(err)
+		}
+		f.Write(line)
+		f.Close()
+		return
+	}
+
+	r := s.rotationFor(t.stream, t.custom)
+	now := time.Now()
+
+	of := s.files[t.stream]
+	if of != nil && of.path != t.path {
+		// changement de date : on ferme l'ancien fichier
+		of.close()
+		of = nil
+		s.cleanupLater(t, r)
+	}
+	if of != nil && r.enabled() && of.needsRotation(r, len(line), now) {
+		of.close()
+		of = nil
+		rotateFile(t.path, now)
+		s.cleanupLater(t, r)
+	}
+	if of == nil {
+		first := s.files[t.stream] == nil
+		of = s.open(t.path, now)
+		s.files[t.stream] = of
+		s.startFlusher()
+		if first {
+			s.cleanupLater(t, r)
+		}
+	}
+
+	n, _ := of.w.Write(line)
+	of.size += int64(n)
+	if flush {
+		of.w.Flush()
+	}
+}
+
+func (s *fileStore) open(path string, now time.Time) *openFile {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		panic(err)
+	}
+	of := &openFile{path: path, f: f, w: bufio.NewWriter(f), opened: now}
+	if info, err := f.Stat(); err == nil {
+		of.size = info.Size()
+	}
+	return of
+}
+
+// ---- compression et rétention en arrière-plan ----
+func (s *fileStore) cleanupLater(t target, r Rotation) {
+	if !r.enabled() {
+		return
+	}
+	s.cleaning.Add(1)
+	go func() {
+		defer s.cleaning.Done()
+		s.cleanMu.Lock()
+		defer s.cleanMu.Unlock()
+		cleanup(t.pattern, t.path, r)
+	}()
+}
+
+// ---- vidage périodique, lancé à la première ouverture ----
+func (s *fileStore) startFlusher() {
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+
+	go func(stop <-chan struct{}, stopped chan<- struct{}) {
+		defer close(stopped)
+		t := time.NewTicker(flushInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				s.Flush()
+			case <-stop:
+				return
+			}
+		}
+	}(s.stop, s.stopped)
+}
+
+// Flush vide les buffers de tous les fichiers ouverts.
+func (s *fileStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, of := range s.files {
+		errs = append(errs, of.w.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// Close vide et ferme tous les fichiers. Les écritures suivantes restent
+// possibles mais ouvrent et ferment le fichier à chaque ligne.
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for stream, of := range s.files {
+		errs = append(errs, of.close())
+		delete(s.files, stream)
+	}
+	stop, stopped := s.stop, s.stopped
+	s.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-stopped
+	}
+	s.cleaning.Wait()
+	return errors.Join(errs...)
+}
+
+func (of *openFile) close() error {
+	return errors.Join(of.w.Flush(), of.f.Close())
+}
@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// SetFromString parses s into v. Types implementing encoding.TextUnmarshaler
+// or flag.Value parse themselves; slices take a comma-separated list and
+// string-keyed maps a comma-separated list of key=value pairs, each element
+// being parsed the same way.
+func SetFromString(v reflect.Value, s string) error {
+	if v.CanAddr() {
+		switch p := v.Addr().Interface().(type) {
+		case encoding.TextUnmarshaler:
+			return p.UnmarshalText([]byte(s))
+		case flag.Value:
+			return p.Set(s)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+
+	case reflect.Slice:
+		items := SplitList(s)
+		sl := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := SetFromString(sl.Index(i), item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		v.Set(sl)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("type %s non supportée", v.Type())
+		}
+		m := reflect.MakeMap(v.Type())
+		for _, item := range SplitList(s) {
+			key, val, ok := strings.Cut(item, "=")
+			if !ok {
+				return fmt.Errorf("%q: expected key=value", item)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := SetFromString(elem, val); err != nil {
+				return fmt.Errorf("[%s]: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)).Convert(v.Type().Key()), elem)
+		}
+		v.Set(m)
+
+	default:
+		return fmt.Errorf("type %s non supportée", v.Type())
+	}
+
+	return nil
+}
+
+// IsTextType reports whether values of t parse themselves from a string
+// (encoding.TextUnmarshaler or flag.Value on *t).
+func IsTextType(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return p.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) ||
+		p.Implements(reflect.TypeOf((*flag.Value)(nil)).Elem())
+}
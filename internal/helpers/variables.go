@@ -54,3 +54,25 @@ func GetFieldIntUniversal(s any, name string) int64 {
 
 	return 0
 }
+
+// GetFieldList reads a string field as a comma-separated list, or a
+// []string field (repeatable flag) whose items may themselves be lists.
+func GetFieldList(s any, name string) []string {
+	rv := reflect.ValueOf(s).Elem()
+	fv := rv.FieldByName(name)
+	if !fv.IsValid() {
+		return nil
+	}
+
+	switch {
+	case fv.Kind() == reflect.String:
+		return SplitList(fv.String())
+	case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.String:
+		var out []string
+		for i := 0; i < fv.Len(); i++ {
+			out = append(out, SplitList(fv.Index(i).String())...)
+		}
+		return out
+	}
+	return nil
+}
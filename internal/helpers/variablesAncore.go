@@ -1,8 +1,21 @@
 package helpers
 
+import "strings"
+
 func GetValue(flg, cfg any, field string) string {
 	if v := GetFieldString(flg, field); v != "" {
 		return v
 	}
 	return GetFieldString(cfg, field)
 }
+
+// SplitList splits a comma-separated flag value, dropping empty items.
+func SplitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}